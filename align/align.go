@@ -0,0 +1,285 @@
+// Package align determines and applies the go.mod changes needed for all
+// modules to require the same version of each package.
+package align
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
+
+	"github.com/MarioCarrion/versions"
+)
+
+type (
+	// Change represents the version update of a package required by a module.
+	Change struct {
+		Module  versions.ModuleName
+		File    string
+		Package versions.PackageName
+		From    string
+		To      string
+	}
+
+	// Config defines how the versions are aligned, Pins indicates the version
+	// to use for a package instead of the highest one required by any module.
+	Config struct {
+		Pins map[versions.PackageName]string `json:"pins"`
+	}
+
+	// PlanOption is configuration option for Plan.
+	PlanOption func(*planOptions)
+
+	planOptions struct {
+		pins map[versions.PackageName]string
+	}
+)
+
+// Apply rewrites the go.mod files using the changes, see Rewrite. The go.sum
+// files are not updated, use Tidy afterwards to do so.
+func Apply(changes []Change) error {
+	for _, file := range Files(changes) {
+		info, err := os.Stat(file)
+		if err != nil {
+			return err
+		}
+
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		res, err := Rewrite(file, data, changes)
+		if err != nil {
+			return err
+		}
+
+		if err := ioutil.WriteFile(file, res, info.Mode()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Body returns the Markdown description of the changes, meant to be used by
+// pull and merge requests.
+func Body(changes []Change) string {
+	var b strings.Builder
+
+	b.WriteString("Aligns the versions of the packages required by all modules.\n\n")
+	b.WriteString("| Module | Package | From | To |\n")
+	b.WriteString("|--------|---------|------|----|\n")
+
+	for _, change := range changes {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", change.Module, change.Package, change.From, change.To)
+	}
+
+	return b.String()
+}
+
+// Files returns the sorted go.mod files modified by the changes.
+func Files(changes []Change) []string {
+	seen := make(map[string]bool)
+
+	var res []string
+
+	for _, change := range changes {
+		if !seen[change.File] {
+			seen[change.File] = true
+			res = append(res, change.File)
+		}
+	}
+
+	sort.Strings(res)
+
+	return res
+}
+
+// LoadConfig returns the configuration defined in the JSON file.
+func LoadConfig(file string) (Config, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return Config{}, err
+	}
+	defer f.Close()
+
+	var config Config
+
+	dec := json.NewDecoder(f)
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(&config); err != nil {
+		return Config{}, fmt.Errorf("invalid config %s: %w", file, err)
+	}
+
+	for name, version := range config.Pins {
+		if !semver.IsValid(version) {
+			return Config{}, fmt.Errorf("invalid config %s: invalid version %q pinned for %s", file, version, name)
+		}
+	}
+
+	return config, nil
+}
+
+// Plan returns the changes needed for all modules to require the same version
+// of each package, the target is the pinned version, see WithPins, or else the
+// highest semantic version required by any module. Packages replaced by a
+// module are ignored, because their required version is not the one being
+// built. Changes are sorted by module and package.
+func Plan(v versions.Versions, opts ...PlanOption) []Change {
+	var options planOptions
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	var res []Change
+
+	for _, name := range v.Packages.Names() {
+		target, pinned := options.pins[name]
+		if !pinned && v.Packages.IsSame(name) {
+			continue
+		}
+
+		pkgs := v.Packages.Values(name)
+
+		if !pinned {
+			for _, pkg := range pkgs {
+				if pkg.ReplacedPath == "" && semver.IsValid(pkg.Version) && (target == "" || semver.Compare(pkg.Version, target) > 0) {
+					target = pkg.Version
+				}
+			}
+		}
+
+		if target == "" {
+			continue
+		}
+
+		for mod, pkg := range pkgs {
+			if pkg.ReplacedPath != "" || !semver.IsValid(pkg.Version) {
+				continue
+			}
+
+			if cmp := semver.Compare(pkg.Version, target); cmp == 0 || (!pinned && cmp > 0) {
+				continue
+			}
+
+			res = append(res, Change{
+				Module:  mod,
+				File:    v.Modules[mod].File,
+				Package: name,
+				From:    pkg.Version,
+				To:      target,
+			})
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Module == res[j].Module {
+			return res[i].Package < res[j].Package
+		}
+
+		return res[i].Module < res[j].Module
+	})
+
+	return res
+}
+
+// Rewrite returns the contents of the go.mod file after applying the changes
+// defined for it, the existing require directives are updated in place so
+// comments, indirect markers and replace directives are kept. Packages
+// required more than once are dropped and required again, only once, using
+// the new version; they are indirect only when all the requirements were.
+func Rewrite(file string, data []byte, changes []Change) ([]byte, error) {
+	f, err := modfile.Parse(file, data, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, change := range changes {
+		if change.File != file {
+			continue
+		}
+
+		if !semver.IsValid(change.To) {
+			return nil, fmt.Errorf("%s: invalid version %q for %s", file, change.To, change.Package)
+		}
+
+		var (
+			path     = string(change.Package)
+			found    int
+			indirect = true
+		)
+
+		for _, req := range f.Require {
+			if req.Mod.Path == path {
+				found++
+				indirect = indirect && req.Indirect
+			}
+		}
+
+		if found > 1 { // duplicate or superseded requirements are replaced by one
+			if err := f.DropRequire(path); err != nil {
+				return nil, err
+			}
+
+			f.AddNewRequire(path, change.To, indirect)
+
+			continue
+		}
+
+		if err := f.AddRequire(path, change.To); err != nil {
+			return nil, err
+		}
+	}
+
+	f.Cleanup()
+
+	return f.Format()
+}
+
+// Tidy runs "go mod tidy" in the directory of each go.mod file, after Apply,
+// so their go.sum files include the hashes of the new requirements; it
+// returns the existing go.sum files. The go command must be installed and
+// able to download the modules.
+func Tidy(changes []Change) ([]string, error) {
+	var res []string
+
+	for _, file := range Files(changes) {
+		var stderr bytes.Buffer
+
+		dir := filepath.Dir(file)
+
+		cmd := exec.Command("go", "mod", "tidy")
+		cmd.Dir = dir
+		cmd.Stderr = &stderr
+
+		if err := cmd.Run(); err != nil {
+			return nil, fmt.Errorf("%s: go mod tidy: %w: %s", file, err, strings.TrimSpace(stderr.String()))
+		}
+
+		sum := filepath.Join(dir, "go.sum")
+		if _, err := os.Stat(sum); err == nil {
+			res = append(res, sum)
+		}
+	}
+
+	return res, nil
+}
+
+// WithPins allows specifying the version to align each package to, instead of
+// the highest one; modules requiring newer versions are downgraded.
+func WithPins(pins map[versions.PackageName]string) PlanOption {
+	return func(o *planOptions) {
+		o.pins = pins
+	}
+}
@@ -0,0 +1,372 @@
+package align_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/MarioCarrion/versions"
+	"github.com/MarioCarrion/versions/align"
+)
+
+func Test_Apply(t *testing.T) {
+	t.Parallel()
+
+	data, err := ioutil.ReadFile(filepath.Join("..", "fixtures", "align", "one.mod"))
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	file := filepath.Join(t.TempDir(), "go.mod")
+
+	if err := ioutil.WriteFile(file, data, 0644); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	changes := []align.Change{
+		{
+			Module:  "fixture.com/align/one",
+			File:    file,
+			Package: "github.com/MarioCarrion/nit",
+			From:    "v1.23.1",
+			To:      "v1.24.0",
+		},
+	}
+
+	if err := align.Apply(changes); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	actual, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	expected := `// Module one.
+module fixture.com/align/one
+
+go 1.15
+
+require (
+	github.com/MarioCarrion/nit v1.24.0 // pinned for a reason
+	github.com/MarioCarrion/swagger-lint v1.0.0 // indirect
+	github.com/google/go-cmp v0.5.0
+)
+
+replace github.com/google/go-cmp => ../go-cmp
+`
+
+	if string(actual) != expected {
+		t.Fatalf("expected values do not match: %s", cmp.Diff(expected, string(actual)))
+	}
+}
+
+func Test_Body(t *testing.T) {
+	t.Parallel()
+
+	actual := align.Body([]align.Change{
+		{
+			Module:  "fixture.com/align/one",
+			Package: "github.com/MarioCarrion/nit",
+			From:    "v1.23.1",
+			To:      "v1.24.0",
+		},
+	})
+
+	expected := `Aligns the versions of the packages required by all modules.
+
+| Module | Package | From | To |
+|--------|---------|------|----|
+| fixture.com/align/one | github.com/MarioCarrion/nit | v1.23.1 | v1.24.0 |
+`
+
+	if actual != expected {
+		t.Fatalf("expected values do not match: %s", cmp.Diff(expected, actual))
+	}
+}
+
+func Test_LoadConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    string
+		expected align.Config
+		err      bool
+	}{
+		{
+			"OK",
+			"config.json",
+			align.Config{
+				Pins: map[versions.PackageName]string{
+					"github.com/MarioCarrion/nit": "v1.23.5",
+				},
+			},
+			false,
+		},
+		{
+			"ERR: invalid version",
+			"invalid_version.json",
+			align.Config{},
+			true,
+		},
+		{
+			"ERR: invalid field",
+			"invalid_field.json",
+			align.Config{},
+			true,
+		},
+		{
+			"ERR: missing file",
+			"missing.json",
+			align.Config{},
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := align.LoadConfig(filepath.Join("..", "fixtures", "align", test.input))
+			if (err != nil) != test.err {
+				t.Fatalf("expected error %t, got %v", test.err, err)
+			}
+
+			if !cmp.Equal(actual, test.expected) {
+				t.Fatalf("expected values do not match: %s", cmp.Diff(test.expected, actual))
+			}
+		})
+	}
+}
+
+func Test_Plan(t *testing.T) {
+	t.Parallel()
+
+	one := filepath.Join("..", "fixtures", "align", "one.mod")
+	two := filepath.Join("..", "fixtures", "align", "two.mod")
+
+	v, err := versions.New([]string{one, two}, versions.WithoutLicenses())
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	expected := []align.Change{
+		{
+			Module:  "fixture.com/align/one",
+			File:    one,
+			Package: "github.com/MarioCarrion/nit",
+			From:    "v1.23.1",
+			To:      "v1.24.0",
+		},
+		{
+			Module:  "fixture.com/align/one",
+			File:    one,
+			Package: "github.com/MarioCarrion/swagger-lint",
+			From:    "v1.0.0",
+			To:      "v1.2.0",
+		},
+	}
+
+	if actual := align.Plan(v); !cmp.Equal(actual, expected) {
+		t.Fatalf("expected values do not match: %s", cmp.Diff(expected, actual))
+	}
+
+	pinned := []align.Change{
+		{
+			Module:  "fixture.com/align/one",
+			File:    one,
+			Package: "github.com/MarioCarrion/nit",
+			From:    "v1.23.1",
+			To:      "v1.23.5",
+		},
+		expected[1],
+		{
+			Module:  "fixture.com/align/two",
+			File:    two,
+			Package: "github.com/MarioCarrion/nit",
+			From:    "v1.24.0",
+			To:      "v1.23.5",
+		},
+		{
+			Module:  "fixture.com/align/two",
+			File:    two,
+			Package: "github.com/google/go-cmp",
+			From:    "v0.6.0",
+			To:      "v0.5.9",
+		},
+	}
+
+	actual := align.Plan(v, align.WithPins(map[versions.PackageName]string{
+		"github.com/MarioCarrion/nit": "v1.23.5",
+		"github.com/google/go-cmp":    "v0.5.9",
+	}))
+	if !cmp.Equal(actual, pinned) {
+		t.Fatalf("expected values do not match: %s", cmp.Diff(pinned, actual))
+	}
+
+	if actual := align.Files(expected); !cmp.Equal(actual, []string{one}) {
+		t.Fatalf("expected values do not match: %s", cmp.Diff([]string{one}, actual))
+	}
+}
+
+func Test_Rewrite(t *testing.T) {
+	t.Parallel()
+
+	var (
+		file      = filepath.Join("..", "fixtures", "align", "two.mod")
+		duplicate = filepath.Join("..", "fixtures", "align", "duplicate.mod")
+	)
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	tests := []struct {
+		name     string
+		input    []align.Change
+		expected string
+		err      bool
+	}{
+		{
+			"OK: other file",
+			[]align.Change{
+				{
+					File:    "other.mod",
+					Package: "github.com/MarioCarrion/nit",
+					To:      "v2.0.0",
+				},
+			},
+			string(data),
+			false,
+		},
+		{
+			"OK",
+			[]align.Change{
+				{
+					File:    file,
+					Package: "github.com/google/go-cmp",
+					From:    "v0.6.0",
+					To:      "v0.7.0",
+				},
+			},
+			`module fixture.com/align/two
+
+go 1.15
+
+require (
+	github.com/MarioCarrion/nit v1.24.0
+	github.com/MarioCarrion/swagger-lint v1.2.0
+	github.com/google/go-cmp v0.7.0
+)
+`,
+			false,
+		},
+		{
+			"OK: duplicate requirements",
+			[]align.Change{
+				{
+					File:    duplicate,
+					Package: "github.com/MarioCarrion/nit",
+					From:    "v1.23.1",
+					To:      "v1.25.0",
+				},
+			},
+			`module fixture.com/align/duplicate
+
+go 1.15
+
+require (
+	github.com/google/go-cmp v0.5.0
+	github.com/MarioCarrion/nit v1.25.0
+)
+`,
+			false,
+		},
+		{
+			"ERR: invalid version",
+			[]align.Change{
+				{
+					File:    file,
+					Package: "github.com/google/go-cmp",
+					To:      "latest",
+				},
+			},
+			"",
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			name := file
+			if len(test.input) > 0 && test.input[0].File == duplicate {
+				name = duplicate
+			}
+
+			data, err := ioutil.ReadFile(name)
+			if err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+
+			actual, err := align.Rewrite(name, data, test.input)
+			if (err != nil) != test.err {
+				t.Fatalf("expected error %t, got %v", test.err, err)
+			}
+
+			if string(actual) != test.expected {
+				t.Fatalf("expected values do not match: %s", cmp.Diff(test.expected, string(actual)))
+			}
+		})
+	}
+}
+
+func Test_Tidy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+		err   bool
+	}{
+		{
+			"OK",
+			"module fixture.com/align/tidy\n\ngo 1.15\n",
+			false,
+		},
+		{
+			"ERR: unknown module",
+			"module fixture.com/align/tidy\n\ngo 1.15\n\nrequire example.com/missing v1.0.0\n",
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			file := filepath.Join(t.TempDir(), "go.mod")
+
+			if err := ioutil.WriteFile(file, []byte(test.input), 0644); err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+
+			sums, err := align.Tidy([]align.Change{{File: file}})
+			if (err != nil) != test.err {
+				t.Fatalf("expected error %t, got %v", test.err, err)
+			}
+
+			if len(sums) != 0 {
+				t.Fatalf("expected no go.sum files, got %v", sums)
+			}
+		})
+	}
+}
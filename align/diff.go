@@ -0,0 +1,180 @@
+package align
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+type (
+	diffOp struct {
+		kind byte // ' ', '-' or '+'
+		line string
+	}
+)
+
+const (
+	// diffContext is the number of unchanged lines surrounding each hunk.
+	diffContext = 3
+)
+
+// Diff returns the unified diff of the changes applied to the go.mod files,
+// without modifying them; files are sorted by name.
+func Diff(changes []Change) (string, error) {
+	var b strings.Builder
+
+	for _, file := range Files(changes) {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return "", err
+		}
+
+		res, err := Rewrite(file, data, changes)
+		if err != nil {
+			return "", err
+		}
+
+		b.WriteString(unifiedDiff(file, string(data), string(res)))
+	}
+
+	return b.String(), nil
+}
+
+func diffLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// diffOps returns the operations to transform a into b, using the longest
+// common subsequence of lines; go.mod files are small enough for the
+// quadratic cost.
+func diffOps(a, b []string) []diffOp {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	res := make([]diffOp, 0, len(a)+len(b))
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			res = append(res, diffOp{' ', a[i]})
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			res = append(res, diffOp{'-', a[i]})
+			i++
+		default:
+			res = append(res, diffOp{'+', b[j]})
+			j++
+		}
+	}
+
+	return res
+}
+
+// unifiedDiff returns the unified diff between the old and new contents of
+// the file, or an empty string when both are the same.
+func unifiedDiff(file, old, new string) string {
+	ops := diffOps(diffLines(old), diffLines(new))
+
+	var changed []int
+
+	for i, op := range ops {
+		if op.kind != ' ' {
+			changed = append(changed, i)
+		}
+	}
+
+	if len(changed) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", file, file)
+
+	for len(changed) > 0 {
+		// Group the changes whose contexts touch or overlap into the same hunk.
+		last := 0
+		for last+1 < len(changed) && changed[last+1]-changed[last] <= 2*diffContext+1 {
+			last++
+		}
+
+		start, end := changed[0]-diffContext, changed[last]+diffContext+1
+		if start < 0 {
+			start = 0
+		}
+
+		if end > len(ops) {
+			end = len(ops)
+		}
+
+		writeHunk(&b, ops, start, end)
+
+		changed = changed[last+1:]
+	}
+
+	return b.String()
+}
+
+func writeHunk(b *strings.Builder, ops []diffOp, start, end int) {
+	var oldStart, newStart, oldCount, newCount int
+
+	for i, op := range ops[:end] {
+		if op.kind != '+' {
+			if i < start {
+				oldStart++
+			} else {
+				oldCount++
+			}
+		}
+
+		if op.kind != '-' {
+			if i < start {
+				newStart++
+			} else {
+				newCount++
+			}
+		}
+	}
+
+	// Line numbers are 1-based, unless the range is empty.
+	if oldCount > 0 {
+		oldStart++
+	}
+
+	if newCount > 0 {
+		newStart++
+	}
+
+	fmt.Fprintf(b, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+
+	for _, op := range ops[start:end] {
+		b.WriteByte(op.kind)
+		b.WriteString(op.line)
+
+		if !strings.HasSuffix(op.line, "\n") {
+			b.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
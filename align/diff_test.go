@@ -0,0 +1,141 @@
+package align_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/MarioCarrion/versions/align"
+)
+
+func Test_Diff(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    string
+		changes  []align.Change
+		expected string
+	}{
+		{
+			"OK: no changes",
+			"module example.com/one\n\nrequire github.com/google/go-cmp v0.5.0\n",
+			nil,
+			"",
+		},
+		{
+			"OK: single hunk",
+			"module example.com/one\n\ngo 1.15\n\nrequire (\n\tgithub.com/MarioCarrion/nit v1.23.1 // indirect\n\tgithub.com/google/go-cmp v0.5.0\n)\n",
+			[]align.Change{
+				{Package: "github.com/google/go-cmp", From: "v0.5.0", To: "v0.6.0"},
+			},
+			`--- FILE
++++ FILE
+@@ -4,5 +4,5 @@
+ 
+ require (
+ 	github.com/MarioCarrion/nit v1.23.1 // indirect
+-	github.com/google/go-cmp v0.5.0
++	github.com/google/go-cmp v0.6.0
+ )
+`,
+		},
+		{
+			"OK: multiple hunks",
+			`module example.com/one
+
+go 1.15
+
+require (
+	github.com/MarioCarrion/nit v1.23.1
+	a.com/a v1.0.0
+	b.com/b v1.0.0
+	c.com/c v1.0.0
+	d.com/d v1.0.0
+	e.com/e v1.0.0
+	f.com/f v1.0.0
+	g.com/g v1.0.0
+	github.com/google/go-cmp v0.5.0
+)
+`,
+			[]align.Change{
+				{Package: "github.com/MarioCarrion/nit", From: "v1.23.1", To: "v1.24.0"},
+				{Package: "github.com/google/go-cmp", From: "v0.5.0", To: "v0.6.0"},
+			},
+			`--- FILE
++++ FILE
+@@ -3,7 +3,7 @@
+ go 1.15
+ 
+ require (
+-	github.com/MarioCarrion/nit v1.23.1
++	github.com/MarioCarrion/nit v1.24.0
+ 	a.com/a v1.0.0
+ 	b.com/b v1.0.0
+ 	c.com/c v1.0.0
+@@ -11,5 +11,5 @@
+ 	e.com/e v1.0.0
+ 	f.com/f v1.0.0
+ 	g.com/g v1.0.0
+-	github.com/google/go-cmp v0.5.0
++	github.com/google/go-cmp v0.6.0
+ )
+`,
+		},
+		{
+			"OK: no newline at end of file",
+			"module example.com/one\n\nrequire github.com/google/go-cmp v0.5.0",
+			[]align.Change{
+				{Package: "github.com/google/go-cmp", From: "v0.5.0", To: "v0.6.0"},
+			},
+			`--- FILE
++++ FILE
+@@ -1,3 +1,3 @@
+ module example.com/one
+ 
+-require github.com/google/go-cmp v0.5.0
+\ No newline at end of file
++require github.com/google/go-cmp v0.6.0
+`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			file := filepath.Join(t.TempDir(), "go.mod")
+
+			if err := ioutil.WriteFile(file, []byte(test.input), 0644); err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+
+			for i := range test.changes {
+				test.changes[i].File = file
+			}
+
+			actual, err := align.Diff(test.changes)
+			if err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+
+			expected := strings.ReplaceAll(test.expected, "FILE", file)
+			if actual != expected {
+				t.Fatalf("expected values do not match: %s", cmp.Diff(expected, actual))
+			}
+
+			data, err := ioutil.ReadFile(file)
+			if err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+
+			if string(data) != test.input {
+				t.Fatalf("expected file to not be modified, got %s", data)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package align
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/MarioCarrion/versions/git"
+)
+
+type (
+	// Publisher commits the changes to a new branch in each local git
+	// repository, pushes it and opens a request to merge it.
+	Publisher struct {
+		requester Requester
+		branch    string
+		base      string
+		remote    string
+		title     string
+		tidy      bool
+	}
+
+	// PublisherOption is configuration option for the Publisher.
+	PublisherOption func(*Publisher)
+
+	// Request represents a pull or merge request, to merge Head into Base.
+	Request struct {
+		Title string
+		Body  string
+		Head  string
+		Base  string
+	}
+
+	// Requester opens pull or merge requests in the hosting service of the
+	// repository identified by the remote URL, returning the request URL.
+	Requester interface {
+		Request(ctx context.Context, remote string, req Request) (string, error)
+	}
+
+	// Result represents the request opened for a repository.
+	Result struct {
+		Repository string
+		URL        string
+		Changes    []Change
+	}
+)
+
+const (
+	// DefaultBase is the default branch the requests are merged into.
+	DefaultBase = "main"
+
+	// DefaultBranch is the default branch including the changes.
+	DefaultBranch = "versions/align"
+
+	// DefaultRemote is the default remote the branch is pushed to.
+	DefaultRemote = "origin"
+
+	// DefaultTitle is the default title of the commits and requests.
+	DefaultTitle = "Align dependency versions"
+)
+
+// NewPublisher returns a Publisher opening requests with the requester.
+func NewPublisher(requester Requester, opts ...PublisherOption) *Publisher {
+	p := Publisher{
+		requester: requester,
+		branch:    DefaultBranch,
+		base:      DefaultBase,
+		remote:    DefaultRemote,
+		title:     DefaultTitle,
+	}
+
+	for _, opt := range opts {
+		opt(&p)
+	}
+
+	return &p
+}
+
+// WithBase allows specifying the branch the requests are merged into.
+func WithBase(base string) PublisherOption {
+	return func(p *Publisher) {
+		p.base = base
+	}
+}
+
+// WithBranch allows specifying the branch including the changes.
+func WithBranch(branch string) PublisherOption {
+	return func(p *Publisher) {
+		p.branch = branch
+	}
+}
+
+// WithRemote allows specifying the remote the branch is pushed to.
+func WithRemote(remote string) PublisherOption {
+	return func(p *Publisher) {
+		p.remote = remote
+	}
+}
+
+// WithTidy allows updating the go.sum files, using Tidy, and committing them
+// with the go.mod files; otherwise they are left as they are.
+func WithTidy() PublisherOption {
+	return func(p *Publisher) {
+		p.tidy = true
+	}
+}
+
+// WithTitle allows specifying the title of the commits and requests.
+func WithTitle(title string) PublisherOption {
+	return func(p *Publisher) {
+		p.title = title
+	}
+}
+
+// Publish groups the changes by git repository and, for each one, creates the
+// branch, applies and commits the changes, pushes the branch and opens a
+// request; the originally checked out branch is restored afterwards. Results
+// are sorted by repository.
+func (p *Publisher) Publish(ctx context.Context, changes []Change) ([]Result, error) {
+	var (
+		repos   = make(map[string]git.Repository)
+		grouped = make(map[string][]Change)
+	)
+
+	for _, change := range changes {
+		repo, err := git.Open(filepath.Dir(change.File))
+		if err != nil {
+			return nil, err
+		}
+
+		repos[repo.Root()] = repo
+		grouped[repo.Root()] = append(grouped[repo.Root()], change)
+	}
+
+	roots := make([]string, 0, len(repos))
+	for root := range repos {
+		roots = append(roots, root)
+	}
+
+	sort.Strings(roots)
+
+	res := make([]Result, 0, len(roots))
+
+	for _, root := range roots {
+		url, err := p.publish(ctx, repos[root], grouped[root])
+		if err != nil {
+			return res, fmt.Errorf("%s: %w", root, err)
+		}
+
+		res = append(res, Result{
+			Repository: root,
+			URL:        url,
+			Changes:    grouped[root],
+		})
+	}
+
+	return res, nil
+}
+
+func (p *Publisher) commit(repo git.Repository, changes []Change) error {
+	if err := Apply(changes); err != nil {
+		return err
+	}
+
+	files := Files(changes)
+
+	if p.tidy {
+		sums, err := Tidy(changes)
+		if err != nil {
+			return err
+		}
+
+		files = append(files, sums...)
+	}
+
+	for i, file := range files {
+		abs, err := filepath.Abs(file)
+		if err != nil {
+			return err
+		}
+
+		files[i] = abs
+	}
+
+	return repo.Commit(p.title, files...)
+}
+
+func (p *Publisher) publish(ctx context.Context, repo git.Repository, changes []Change) (string, error) {
+	remote, err := repo.RemoteURL(p.remote)
+	if err != nil {
+		return "", err
+	}
+
+	current, err := repo.CurrentBranch()
+	if err != nil {
+		return "", err
+	}
+
+	if err := repo.CreateBranch(p.branch); err != nil {
+		return "", err
+	}
+
+	if err := p.commit(repo, changes); err != nil {
+		_ = repo.Checkout(current)
+		return "", err
+	}
+
+	if err := repo.Checkout(current); err != nil {
+		return "", err
+	}
+
+	if err := repo.Push(p.remote, p.branch); err != nil {
+		return "", err
+	}
+
+	return p.requester.Request(ctx, remote, Request{
+		Title: p.title,
+		Body:  Body(changes),
+		Head:  p.branch,
+		Base:  p.base,
+	})
+}
@@ -0,0 +1,119 @@
+package align_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/MarioCarrion/versions/align"
+)
+
+type (
+	fakeRequester struct {
+		remote string
+		req    align.Request
+	}
+)
+
+func Test_Publisher_Publish(t *testing.T) {
+	t.Parallel()
+
+	remote := t.TempDir()
+	dir := t.TempDir()
+
+	execGit(t, remote, "init", "--bare")
+	execGit(t, dir, "init")
+	execGit(t, dir, "checkout", "-b", "main")
+	execGit(t, dir, "config", "user.email", "versions@example.com")
+	execGit(t, dir, "config", "user.name", "versions")
+	execGit(t, dir, "remote", "add", "origin", remote)
+
+	file := filepath.Join(dir, "go.mod")
+
+	if err := ioutil.WriteFile(file, []byte("module example.com/one\n\ngo 1.15\n\nrequire github.com/google/go-cmp v0.5.0\n"), 0644); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	execGit(t, dir, "add", "go.mod")
+	execGit(t, dir, "commit", "-m", "initial")
+
+	changes := []align.Change{
+		{
+			Module:  "example.com/one",
+			File:    file,
+			Package: "github.com/google/go-cmp",
+			From:    "v0.5.0",
+			To:      "v0.6.0",
+		},
+	}
+
+	requester := fakeRequester{}
+
+	res, err := align.NewPublisher(&requester,
+		align.WithBase("main"),
+		align.WithBranch("align"),
+		align.WithRemote("origin"),
+		align.WithTitle("Align")).Publish(context.Background(), changes)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	root := execGit(t, dir, "rev-parse", "--show-toplevel")
+
+	expected := []align.Result{
+		{
+			Repository: root,
+			URL:        "https://example.com/pull/1",
+			Changes:    changes,
+		},
+	}
+
+	if !cmp.Equal(res, expected) {
+		t.Fatalf("expected values do not match: %s", cmp.Diff(expected, res))
+	}
+
+	expectedReq := align.Request{
+		Title: "Align",
+		Body:  align.Body(changes),
+		Head:  "align",
+		Base:  "main",
+	}
+
+	if !cmp.Equal(requester.req, expectedReq) || requester.remote != remote {
+		t.Fatalf("expected values do not match: %s", cmp.Diff(expectedReq, requester.req))
+	}
+
+	if branch := execGit(t, dir, "rev-parse", "--abbrev-ref", "HEAD"); branch != "main" {
+		t.Fatalf("expected main to be restored, got %s", branch)
+	}
+
+	if actual := execGit(t, remote, "show", "align:go.mod"); !strings.Contains(actual, "github.com/google/go-cmp v0.6.0") {
+		t.Fatalf("expected pushed go.mod to be updated, got %s", actual)
+	}
+}
+
+func execGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %s: %s", args, err, out)
+	}
+
+	return strings.TrimSpace(string(out))
+}
+
+func (f *fakeRequester) Request(_ context.Context, remote string, req align.Request) (string, error) {
+	f.remote = remote
+	f.req = req
+
+	return "https://example.com/pull/1", nil
+}
@@ -0,0 +1,118 @@
+//go:build go1.18
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/MarioCarrion/versions"
+	"github.com/MarioCarrion/versions/align"
+)
+
+type (
+	// planFlags defines the flags shared by the commands aligning versions.
+	planFlags struct {
+		dirs, excludes stringsFlag
+		config         string
+		tidy           bool
+	}
+)
+
+// alignCommand rewrites the go.mod files requiring versions different to the
+// aligned one, or prints the unified diff when using -dry-run.
+func alignCommand(args []string) {
+	var (
+		flags  planFlags
+		dryRun bool
+	)
+
+	fs := flag.NewFlagSet("align", flag.ExitOnError)
+	flags.register(fs)
+	fs.BoolVar(&dryRun, "dry-run", false, "print the unified diff of the changes instead of rewriting the files")
+	_ = fs.Parse(args)
+
+	changes := flags.plan(fs)
+
+	if dryRun {
+		diff, err := align.Diff(changes)
+		if err != nil {
+			fmt.Printf("error aligning versions %s\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Print(diff)
+
+		return
+	}
+
+	if err := align.Apply(changes); err != nil {
+		fmt.Printf("error aligning versions %s\n", err)
+		os.Exit(1)
+	}
+
+	if flags.tidy {
+		if _, err := align.Tidy(changes); err != nil {
+			fmt.Printf("error updating go.sum files %s\n", err)
+			os.Exit(1)
+		}
+	}
+
+	for _, change := range changes {
+		fmt.Printf("%s: %s %s => %s\n", change.File, change.Package, change.From, change.To)
+	}
+}
+
+// plan returns the changes needed to align the versions of the go.mod files
+// indicated by the parsed flags and arguments.
+func (p *planFlags) plan(fs *flag.FlagSet) []align.Change {
+	params := fs.Args()
+
+	if len(p.dirs) > 0 {
+		discovered, err := versions.Discover(p.dirs, p.excludes)
+		if err != nil {
+			fmt.Printf("error discovering files %s\n", err)
+			os.Exit(1)
+		}
+
+		params = append(params, discovered...)
+	}
+
+	if len(params) == 0 {
+		fmt.Println("path to go.mod or go.work files required")
+		os.Exit(1)
+	}
+
+	var opts []align.PlanOption
+
+	if p.config != "" {
+		config, err := align.LoadConfig(p.config)
+		if err != nil {
+			fmt.Printf("error loading config %s\n", err)
+			os.Exit(1)
+		}
+
+		opts = append(opts, align.WithPins(config.Pins))
+	}
+
+	gomods, err := versions.New(params, versions.WithoutLicenses())
+	if err != nil {
+		fmt.Printf("error parsing files %s\n", err)
+		os.Exit(1)
+	}
+
+	return align.Plan(gomods, opts...)
+}
+
+// register defines the flags in fs, including its usage.
+func (p *planFlags) register(fs *flag.FlagSet) {
+	fs.Var(&p.dirs, "dir", "root directory to recursively discover go.mod files in, can be repeated")
+	fs.Var(&p.excludes, "exclude", ".gitignore-style pattern to exclude when discovering, can be repeated")
+	fs.StringVar(&p.config, "config", "", "JSON file defining the versions pinned for each package, instead of the highest one")
+	fs.BoolVar(&p.tidy, "tidy", false, "run \"go mod tidy\" after rewriting the go.mod files to update their go.sum files")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: versions %s [flags] [path to go.mod or go.work ...]\n", fs.Name())
+		fs.PrintDefaults()
+	}
+}
@@ -0,0 +1,54 @@
+//go:build go1.18
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/MarioCarrion/versions"
+	"github.com/MarioCarrion/versions/cyclonedx"
+)
+
+// cyclonedxCommand generates a CycloneDX BOM per module, in JSON or XML
+// format.
+func cyclonedxCommand(args []string) {
+	var (
+		flags  sbomFlags
+		format string
+	)
+
+	fs := flag.NewFlagSet("cyclonedx", flag.ExitOnError)
+	flags.register(fs)
+	fs.StringVar(&format, "format", "json", "output format, one of: json, xml")
+	_ = fs.Parse(args)
+
+	var ext string
+
+	switch format {
+	case "json":
+		ext = ".cdx.json"
+	case "xml":
+		ext = ".cdx.xml"
+	default:
+		fmt.Printf("invalid format %s\n", format)
+		os.Exit(1)
+	}
+
+	gomods := flags.parse(fs)
+
+	flags.write(gomods, ext, func(name versions.ModuleName, w io.Writer) error {
+		bom, err := cyclonedx.NewBOM(gomods, name)
+		if err != nil {
+			return err
+		}
+
+		if format == "json" {
+			return bom.RenderJSON(w)
+		}
+
+		return bom.RenderXML(w)
+	})
+}
@@ -0,0 +1,67 @@
+//go:build go1.18
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/MarioCarrion/versions"
+	"github.com/MarioCarrion/versions/diff"
+	"github.com/MarioCarrion/versions/json"
+)
+
+// diffCommand reports the changes between two snapshots, rendered using the
+// "json" format, in Markdown or JSON format.
+func diffCommand(args []string) {
+	var format string
+
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	fs.StringVar(&format, "format", "markdown", "output format, one of: markdown, json")
+	_ = fs.Parse(args)
+
+	if format != "markdown" && format != "json" {
+		fmt.Printf("invalid format %s\n", format)
+		os.Exit(1)
+	}
+
+	if fs.NArg() != 2 {
+		fmt.Println("paths to old and new snapshots required")
+		os.Exit(1)
+	}
+
+	report, err := diff.Compare(readSnapshot(fs.Arg(0)), readSnapshot(fs.Arg(1)))
+	if err != nil {
+		fmt.Printf("error comparing snapshots %s\n", err)
+		os.Exit(1)
+	}
+
+	render := report.RenderMarkdown
+	if format == "json" {
+		render = report.RenderJSON
+	}
+
+	if err := render(os.Stdout); err != nil {
+		fmt.Printf("error rendering changes %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// readSnapshot returns the versions included in the snapshot file.
+func readSnapshot(file string) versions.Versions {
+	f, err := os.Open(file)
+	if err != nil {
+		fmt.Printf("error opening snapshot %s\n", err)
+		os.Exit(1)
+	}
+	defer f.Close()
+
+	v, err := json.Decode(f)
+	if err != nil {
+		fmt.Printf("error reading snapshot %s: %s\n", file, err)
+		os.Exit(1)
+	}
+
+	return v
+}
@@ -0,0 +1,25 @@
+//go:build go1.18
+
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/MarioCarrion/versions/align"
+	"github.com/MarioCarrion/versions/github"
+)
+
+// githubCommand opens GitHub pull requests updating the go.mod files requiring
+// older versions of the packages used by other modules.
+func githubCommand(args []string) {
+	var token, api string
+
+	fs := flag.NewFlagSet("github", flag.ExitOnError)
+	fs.StringVar(&token, "token", os.Getenv("GITHUB_TOKEN"), "token used to authenticate to the GitHub API")
+	fs.StringVar(&api, "api", github.DefaultBaseURL, "URL of the GitHub API")
+
+	publishCommand(fs, args, func() align.Requester {
+		return github.NewClient(token, github.WithBaseURL(api))
+	})
+}
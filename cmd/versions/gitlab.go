@@ -0,0 +1,33 @@
+//go:build go1.18
+
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/MarioCarrion/versions/align"
+	"github.com/MarioCarrion/versions/gitlab"
+)
+
+// gitlabCommand opens GitLab merge requests updating the go.mod files
+// requiring older versions of the packages used by other modules.
+func gitlabCommand(args []string) {
+	var (
+		token, api        string
+		labels, assignees stringsFlag
+	)
+
+	fs := flag.NewFlagSet("gitlab", flag.ExitOnError)
+	fs.StringVar(&token, "token", os.Getenv("GITLAB_TOKEN"), "token used to authenticate to the GitLab API")
+	fs.StringVar(&api, "api", gitlab.DefaultBaseURL, "URL of the GitLab API")
+	fs.Var(&labels, "label", "label added to the merge requests, can be repeated")
+	fs.Var(&assignees, "assignee", "username of the user assigned to the merge requests, can be repeated")
+
+	publishCommand(fs, args, func() align.Requester {
+		return gitlab.NewClient(token,
+			gitlab.WithAssignees(assignees...),
+			gitlab.WithBaseURL(api),
+			gitlab.WithLabels(labels...))
+	})
+}
@@ -1,32 +1,260 @@
-// +build go1.15
+//go:build go1.18
 
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
+	"runtime"
+	"sort"
+	"strings"
 
 	"github.com/MarioCarrion/versions"
-	"github.com/MarioCarrion/versions/markdown"
+	_ "github.com/MarioCarrion/versions/csv"
+	"github.com/MarioCarrion/versions/goproxy"
+	_ "github.com/MarioCarrion/versions/graphviz"
+	_ "github.com/MarioCarrion/versions/html"
+	_ "github.com/MarioCarrion/versions/json"
+	_ "github.com/MarioCarrion/versions/markdown"
+	"github.com/MarioCarrion/versions/osv"
+	"github.com/MarioCarrion/versions/policy"
 )
 
+type (
+	stringsFlag []string
+)
+
+func defaultLicenseCacheDir() string {
+	dir, err := versions.DefaultLicenseCacheDir()
+	if err != nil {
+		return ""
+	}
+
+	return dir
+}
+
+// goEnv returns the value of the environment variable, defaulting to GOPRIVATE
+// like the go command does for GONOPROXY and GONOSUMDB.
+func goEnv(name string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+
+	return os.Getenv("GOPRIVATE")
+}
+
 func main() {
-	params := os.Args[1:]
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "align":
+			alignCommand(os.Args[2:])
+			return
+		case "cyclonedx":
+			cyclonedxCommand(os.Args[2:])
+			return
+		case "diff":
+			diffCommand(os.Args[2:])
+			return
+		case "github":
+			githubCommand(os.Args[2:])
+			return
+		case "gitlab":
+			gitlabCommand(os.Args[2:])
+			return
+		case "spdx":
+			spdxCommand(os.Args[2:])
+			return
+		}
+	}
+
+	var (
+		dirs, excludes stringsFlag
+		updates, mvs   bool
+		download       bool
+		coupling       bool
+		why, format    string
+		policyFile     string
+		vulnDB         string
+		concurrency    int
+		licenseCache   string
+		licenseStatus  stringsFlag
+	)
+
+	flag.Var(&dirs, "dir", "root directory to recursively discover go.mod files in, can be repeated")
+	flag.Var(&excludes, "exclude", ".gitignore-style pattern to exclude when discovering, can be repeated")
+	flag.StringVar(&format, "format", "markdown", fmt.Sprintf("output format, one of: %s", strings.Join(versions.Renderers(), ", ")))
+	flag.BoolVar(&coupling, "coupling", false, "include the coupling metrics in the output, always included by the graphviz and json formats")
+	flag.IntVar(&concurrency, "concurrency", runtime.NumCPU(), "maximum number of licenses detected concurrently")
+	flag.StringVar(&licenseCache, "license-cache", defaultLicenseCacheDir(), "directory used to persist detected licenses, empty disables it")
+	flag.Var(&licenseStatus, "license-status", "only include packages with the license status: detected, unrecognized, not-found, not-downloaded or error, can be repeated")
+	flag.BoolVar(&download, "download", false, "download, using GOPROXY, the modules missing in the module cache before detecting licenses")
+	flag.BoolVar(&updates, "updates", false, "determine the newest versions available using GOPROXY")
+	flag.BoolVar(&mvs, "mvs", false, "report the versions selected by Minimal Version Selection, using the module cache")
+	flag.StringVar(&policyFile, "policy", "", "JSON file defining the license policy, exits non-zero when denied licenses are used")
+	flag.StringVar(&vulnDB, "vulndb", "", "Go vulnerability database, in OSV format, used to report vulnerabilities: a local directory or an HTTP(S) mirror URL, empty disables it")
+	flag.StringVar(&why, "why", "", "show why each module depends on the package, using the module cache")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: versions [flags] [path to go.mod or go.work ...]\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "       versions align|cyclonedx|github|gitlab|spdx [flags] [path to go.mod or go.work ...]\n")
+		fmt.Fprintf(flag.CommandLine.Output(), "       versions diff [flags] <old snapshot> <new snapshot>\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	params := flag.Args()
+
+	if len(dirs) > 0 {
+		discovered, err := versions.Discover(dirs, excludes)
+		if err != nil {
+			fmt.Printf("error discovering files %s\n", err)
+			os.Exit(1)
+		}
+
+		params = append(params, discovered...)
+	}
+
 	if len(params) == 0 {
-		fmt.Println("path to go.mod files required")
+		fmt.Println("path to go.mod or go.work files required")
 		os.Exit(1)
 	}
 
-	gomods, err := versions.New(params)
+	statuses := make(map[versions.LicenseStatus]bool)
+
+	for _, value := range licenseStatus {
+		switch status := versions.LicenseStatus(value); status {
+		case versions.LicenseStatusDetected, versions.LicenseStatusError, versions.LicenseStatusNotDownloaded,
+			versions.LicenseStatusNotFound, versions.LicenseStatusUnrecognized:
+			statuses[status] = true
+		default:
+			fmt.Printf("invalid license status %s\n", value)
+			os.Exit(1)
+		}
+	}
+
+	var config *policy.Config
+
+	if policyFile != "" {
+		c, err := policy.Load(policyFile)
+		if err != nil {
+			fmt.Printf("error loading policy %s\n", err)
+			os.Exit(1)
+		}
+
+		config = &c
+	}
+
+	opts := []versions.Option{
+		versions.WithConcurrency(concurrency),
+		versions.WithLicenseCache(licenseCache),
+	}
+
+	if updates || download {
+		client, err := goproxy.NewClient(os.Getenv("GOPROXY"), goproxy.WithNoProxy(goEnv("GONOPROXY")))
+		if err != nil {
+			fmt.Printf("error configuring GOPROXY %s\n", err)
+			os.Exit(1)
+		}
+
+		if updates {
+			opts = append(opts, versions.WithUpdates(client))
+		}
+
+		if download {
+			opts = append(opts, versions.WithDownload(client, goEnv("GONOSUMDB")))
+		}
+	}
+
+	if vulnDB != "" {
+		client, err := osv.NewClient(vulnDB)
+		if err != nil {
+			fmt.Printf("error configuring vulnerability database %s\n", err)
+			os.Exit(1)
+		}
+
+		opts = append(opts, versions.WithVulnerabilities(client))
+	}
+
+	if mvs || why != "" {
+		opts = append(opts, versions.WithModuleGraph())
+	}
+
+	gomods, err := versions.New(params, opts...)
 	if err != nil {
 		fmt.Printf("error parsing files %s\n", err)
 		os.Exit(1)
 	}
 
-	md := markdown.NewMarkdown(gomods,
-		markdown.WithModulesSorting(markdown.ModulesSortingAlphabetically),
-		markdown.WithPackagesSorting(markdown.PackagesSortingAlphabeticallySupported),
-		markdown.WithPackagesLicense(true))
+	if why != "" {
+		printWhy(gomods, versions.PackageName(why))
+		return
+	}
+
+	rendered := gomods
+	if len(statuses) > 0 {
+		rendered = gomods.FilterPackages(func(_ versions.ModuleName, pkg versions.Package) bool {
+			return statuses[pkg.License.Status]
+		})
+	}
+
+	renderer, err := versions.NewRenderer(format, rendered, versions.WithRendererCoupling(coupling))
+	if err != nil {
+		fmt.Printf("error rendering %s\n", err)
+		os.Exit(1)
+	}
+
+	if err := renderer.Render(os.Stdout); err != nil {
+		fmt.Printf("error rendering %s\n", err)
+		os.Exit(1)
+	}
+
+	if config != nil {
+		violations := config.Evaluate(gomods)
+
+		for _, violation := range violations {
+			fmt.Fprintln(os.Stderr, violation)
+		}
+
+		if violations.HasDenied() {
+			os.Exit(2)
+		}
+	}
+}
+
+func printWhy(gomods versions.Versions, name versions.PackageName) {
+	paths := gomods.Why(name)
+
+	names := make([]string, 0, len(gomods.Modules))
+	for mod := range gomods.Modules {
+		names = append(names, string(mod))
+	}
+
+	sort.Strings(names)
+
+	for i, mod := range names {
+		if i > 0 {
+			fmt.Println()
+		}
+
+		fmt.Printf("# %s\n", mod)
+
+		path, ok := paths[versions.ModuleName(mod)]
+		if !ok {
+			fmt.Printf("(%s does not depend on %s)\n", mod, name)
+			continue
+		}
+
+		for _, dep := range path {
+			fmt.Println(dep)
+		}
+	}
+}
+
+func (s *stringsFlag) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
 
-	fmt.Println(md.String())
+func (s *stringsFlag) String() string {
+	return strings.Join(*s, ",")
 }
@@ -0,0 +1,59 @@
+//go:build go1.18
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/MarioCarrion/versions/align"
+)
+
+// publishCommand defines the flags shared by the commands opening requests to
+// align the versions, parses the arguments and publishes the changes using
+// the requester returned by newRequester.
+func publishCommand(fs *flag.FlagSet, args []string, newRequester func() align.Requester) {
+	var (
+		flags                       planFlags
+		branch, base, remote, title string
+	)
+
+	flags.register(fs)
+	fs.StringVar(&branch, "branch", align.DefaultBranch, "branch created in each repository to commit the changes to")
+	fs.StringVar(&base, "base", align.DefaultBase, "branch the requests are merged into")
+	fs.StringVar(&remote, "remote", align.DefaultRemote, "git remote the branch is pushed to")
+	fs.StringVar(&title, "title", align.DefaultTitle, "title of the commits and requests")
+	_ = fs.Parse(args)
+
+	changes := flags.plan(fs)
+	if len(changes) == 0 {
+		fmt.Println("all versions are aligned")
+		return
+	}
+
+	opts := []align.PublisherOption{
+		align.WithBase(base),
+		align.WithBranch(branch),
+		align.WithRemote(remote),
+		align.WithTitle(title),
+	}
+
+	if flags.tidy {
+		opts = append(opts, align.WithTidy())
+	}
+
+	publisher := align.NewPublisher(newRequester(), opts...)
+
+	results, err := publisher.Publish(context.Background(), changes)
+
+	for _, res := range results {
+		fmt.Printf("%s: %s\n", res.Repository, res.URL)
+	}
+
+	if err != nil {
+		fmt.Printf("error opening requests %s\n", err)
+		os.Exit(1)
+	}
+}
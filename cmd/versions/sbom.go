@@ -0,0 +1,136 @@
+//go:build go1.18
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/MarioCarrion/versions"
+)
+
+type (
+	// sbomFlags defines the flags shared by the commands generating software
+	// bill of materials.
+	sbomFlags struct {
+		dirs, excludes stringsFlag
+		output         string
+		licenseCache   string
+		mvs            bool
+	}
+)
+
+// writeFile creates the file and writes to it using fn.
+func writeFile(file string, fn func(io.Writer) error) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(f); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// parse returns the versions of the go.mod files indicated by the parsed flags
+// and arguments, including their licenses.
+func (s *sbomFlags) parse(fs *flag.FlagSet) versions.Versions {
+	params := fs.Args()
+
+	if len(s.dirs) > 0 {
+		discovered, err := versions.Discover(s.dirs, s.excludes)
+		if err != nil {
+			fmt.Printf("error discovering files %s\n", err)
+			os.Exit(1)
+		}
+
+		params = append(params, discovered...)
+	}
+
+	if len(params) == 0 {
+		fmt.Println("path to go.mod or go.work files required")
+		os.Exit(1)
+	}
+
+	opts := []versions.Option{
+		versions.WithConcurrency(runtime.NumCPU()),
+		versions.WithLicenseCache(s.licenseCache),
+	}
+
+	if s.mvs {
+		opts = append(opts, versions.WithModuleGraph())
+	}
+
+	gomods, err := versions.New(params, opts...)
+	if err != nil {
+		fmt.Printf("error parsing files %s\n", err)
+		os.Exit(1)
+	}
+
+	return gomods
+}
+
+// register defines the flags in fs, including its usage.
+func (s *sbomFlags) register(fs *flag.FlagSet) {
+	fs.Var(&s.dirs, "dir", "root directory to recursively discover go.mod files in, can be repeated")
+	fs.Var(&s.excludes, "exclude", ".gitignore-style pattern to exclude when discovering, can be repeated")
+	fs.StringVar(&s.output, "output", "", "directory to write one file per module to, required when there are multiple modules, empty writes to stdout")
+	fs.StringVar(&s.licenseCache, "license-cache", defaultLicenseCacheDir(), "directory used to persist detected licenses, empty disables it")
+	fs.BoolVar(&s.mvs, "mvs", false, "use the versions selected by Minimal Version Selection and the module graph, using the module cache")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: versions %s [flags] [path to go.mod or go.work ...]\n", fs.Name())
+		fs.PrintDefaults()
+	}
+}
+
+// write renders the document of each module, sorted by name, to stdout or to
+// a file in the output directory named after the module, using ext.
+func (s *sbomFlags) write(gomods versions.Versions, ext string, render func(versions.ModuleName, io.Writer) error) {
+	names := make([]versions.ModuleName, 0, len(gomods.Modules))
+	for name := range gomods.Modules {
+		names = append(names, name)
+	}
+
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+
+	if s.output == "" {
+		if len(names) > 1 {
+			fmt.Println("-output is required when there are multiple modules")
+			os.Exit(1)
+		}
+
+		for _, name := range names {
+			if err := render(name, os.Stdout); err != nil {
+				fmt.Printf("error generating %s %s\n", name, err)
+				os.Exit(1)
+			}
+		}
+
+		return
+	}
+
+	if err := os.MkdirAll(s.output, 0755); err != nil {
+		fmt.Printf("error creating output %s\n", err)
+		os.Exit(1)
+	}
+
+	for _, name := range names {
+		file := filepath.Join(s.output, strings.ReplaceAll(string(name), "/", "_")+ext)
+
+		if err := writeFile(file, func(w io.Writer) error { return render(name, w) }); err != nil {
+			fmt.Printf("error generating %s %s\n", name, err)
+			os.Exit(1)
+		}
+
+		fmt.Println(file)
+	}
+}
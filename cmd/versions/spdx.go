@@ -0,0 +1,54 @@
+//go:build go1.18
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/MarioCarrion/versions"
+	"github.com/MarioCarrion/versions/spdx"
+)
+
+// spdxCommand generates a SPDX document per module, in JSON or tag-value
+// format.
+func spdxCommand(args []string) {
+	var (
+		flags  sbomFlags
+		format string
+	)
+
+	fs := flag.NewFlagSet("spdx", flag.ExitOnError)
+	flags.register(fs)
+	fs.StringVar(&format, "format", "json", "output format, one of: json, tag-value")
+	_ = fs.Parse(args)
+
+	var ext string
+
+	switch format {
+	case "json":
+		ext = ".spdx.json"
+	case "tag-value":
+		ext = ".spdx"
+	default:
+		fmt.Printf("invalid format %s\n", format)
+		os.Exit(1)
+	}
+
+	gomods := flags.parse(fs)
+
+	flags.write(gomods, ext, func(name versions.ModuleName, w io.Writer) error {
+		doc, err := spdx.NewDocument(gomods, name)
+		if err != nil {
+			return err
+		}
+
+		if format == "json" {
+			return doc.RenderJSON(w)
+		}
+
+		return doc.RenderTagValue(w)
+	})
+}
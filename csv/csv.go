@@ -0,0 +1,249 @@
+// Package csv allows versions to be rendered as the same matrix rendered by
+// the markdown package, but using RFC 4180 CSV or TSV, meant to be used by
+// spreadsheets.
+package csv
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/MarioCarrion/versions"
+	"github.com/MarioCarrion/versions/markdown"
+)
+
+type (
+	// Format is the enum for the delimiter-separated formats.
+	Format uint
+
+	//-
+
+	// CSV renders versions as delimiter-separated values: modules are columns,
+	// each one split into version, replacement and license, and packages are
+	// rows, after the Go version row.
+	CSV struct {
+		versions       versions.Versions
+		format         Format
+		modulesSortBy  markdown.ModulesSorting
+		packagesSortBy markdown.PackagesSorting
+		showCoupling   bool
+	}
+
+	// Option is configuration option for this renderer.
+	Option func(*CSV)
+)
+
+const (
+	// FormatCSV indicates values are separated by commas and records by
+	// CRLF, as defined by RFC 4180.
+	FormatCSV Format = iota
+
+	// FormatTSV indicates values are separated by tabs and records by LF.
+	FormatTSV
+)
+
+// NewCSV instantiates a new template for rendering in CSV or TSV.
+func NewCSV(v versions.Versions, opts ...Option) CSV {
+	c := CSV{
+		versions: v,
+	}
+
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	return c
+}
+
+// WithCoupling allows including the coupling metrics: a row with the
+// efferent (Ce) and afferent (Ca) coupling and instability (I) of each module
+// and a column with the afferent coupling of each package.
+func WithCoupling(opt bool) Option {
+	return func(c *CSV) {
+		c.showCoupling = opt
+	}
+}
+
+// WithFormat allows specifying the delimiter-separated format.
+func WithFormat(opt Format) Option {
+	return func(c *CSV) {
+		c.format = opt
+	}
+}
+
+// WithModulesSorting allows specifying the sorting option for modules, the
+// order they were parsed is used by markdown.ModulesSortingAsInput.
+func WithModulesSorting(opt markdown.ModulesSorting) Option {
+	return func(c *CSV) {
+		c.modulesSortBy = opt
+	}
+}
+
+// WithPackagesSorting allows specifying the sorting option for packages.
+func WithPackagesSorting(opt markdown.PackagesSorting) Option {
+	return func(c *CSV) {
+		c.packagesSortBy = opt
+	}
+}
+
+func init() {
+	for name, format := range map[string]Format{"csv": FormatCSV, "tsv": FormatTSV} {
+		format := format
+
+		versions.RegisterRenderer(name, func(v versions.Versions, o versions.RendererOptions) versions.Renderer {
+			return NewCSV(v,
+				WithCoupling(o.Coupling),
+				WithFormat(format),
+				WithModulesSorting(markdown.ModulesSortingAlphabetically),
+				WithPackagesSorting(markdown.PackagesSortingAlphabeticallySupported))
+		})
+	}
+}
+
+// license returns the SPDX identifier of the license, or its status when it
+// was not detected.
+func license(l versions.License) string {
+	switch l.Status {
+	case versions.LicenseStatusDetected, "":
+		return l.Identifier
+	case versions.LicenseStatusUnrecognized:
+		return string(l.Status) + " " + l.Identifier
+	case versions.LicenseStatusError:
+		return string(l.Status) + ": " + l.Error
+	}
+
+	return string(l.Status)
+}
+
+// Modules returns the names of the modules in the order they are rendered.
+func (c CSV) Modules() []versions.ModuleName {
+	res := make([]versions.ModuleName, 0, len(c.versions.Modules))
+
+	for _, mod := range c.versions.GoVersions.Values() {
+		if _, ok := c.versions.Modules[mod.Name]; ok {
+			res = append(res, mod.Name)
+		}
+	}
+
+	if len(res) != len(c.versions.Modules) { // Modules not set using GoVersions
+		res = res[:0]
+
+		for name := range c.versions.Modules {
+			res = append(res, name)
+		}
+
+		sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	}
+
+	if c.modulesSortBy == markdown.ModulesSortingAlphabetically {
+		sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	}
+
+	return res
+}
+
+// Packages returns the names of the packages in the order they are rendered.
+func (c CSV) Packages() []versions.PackageName {
+	var same, different []versions.PackageName
+
+	for _, name := range c.versions.Packages.Names() {
+		if c.packagesSortBy == markdown.PackagesSortingAlphabeticallySupported && !c.versions.Packages.IsSame(name) {
+			different = append(different, name)
+			continue
+		}
+
+		same = append(same, name)
+	}
+
+	if c.packagesSortBy != markdown.PackagesSortingAsFound {
+		sort.Slice(same, func(i, j int) bool { return same[i] < same[j] })
+		sort.Slice(different, func(i, j int) bool { return different[i] < different[j] })
+	}
+
+	return append(same, different...)
+}
+
+// Records returns the rendered rows: the header, the Go version, the coupling
+// when included, and one per package. Each module uses three columns: version,
+// replacement and license; the afferent coupling of packages is the last
+// column, when included.
+func (c CSV) Records() [][]string {
+	mods := c.Modules()
+	pkgs := c.Packages()
+
+	header := []string{"Name", "Same"}
+	golang := []string{"Go", strconv.FormatBool(c.versions.GoVersions.IsSame())}
+	coupling := []string{"Coupling", ""}
+
+	for _, name := range mods {
+		header = append(header, string(name)+" version", string(name)+" replacement", string(name)+" license")
+		golang = append(golang, string(c.versions.Modules[name].GoVersion), "", "")
+
+		mod := c.versions.ModuleCoupling(name)
+		coupling = append(coupling, fmt.Sprintf("Ce %d, Ca %d, I %.2f", mod.Efferent, mod.Afferent, mod.Instability()), "", "")
+	}
+
+	res := make([][]string, 0, len(pkgs)+3)
+	res = append(res, header, golang)
+
+	if c.showCoupling {
+		res[0] = append(res[0], "Afferent")
+		res[1] = append(res[1], "")
+		res = append(res, append(coupling, ""))
+	}
+
+	for _, pkgName := range pkgs {
+		row := []string{string(pkgName), strconv.FormatBool(c.versions.Packages.IsSame(pkgName))}
+
+		for _, name := range mods {
+			pkg, ok := c.versions.Modules[name].DependencyRequirements[pkgName]
+			if !ok {
+				row = append(row, "", "", "")
+				continue
+			}
+
+			replacement := strings.TrimSpace(pkg.ReplacedPath + " " + pkg.ReplacedVersion)
+
+			row = append(row, pkg.Version, replacement, license(pkg.License))
+		}
+
+		if c.showCoupling {
+			row = append(row, strconv.Itoa(c.versions.PackageCoupling(pkgName).Afferent))
+		}
+
+		res = append(res, row)
+	}
+
+	return res
+}
+
+// Render writes versions in CSV or TSV format to w.
+func (c CSV) Render(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	if c.format == FormatTSV {
+		cw.Comma = '\t'
+	} else {
+		cw.UseCRLF = true
+	}
+
+	if err := cw.WriteAll(c.Records()); err != nil {
+		return err
+	}
+
+	return cw.Error()
+}
+
+// String returns versions in CSV or TSV format.
+func (c CSV) String() string {
+	var b strings.Builder
+
+	if err := c.Render(&b); err != nil {
+		return ""
+	}
+
+	return b.String()
+}
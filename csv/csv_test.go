@@ -0,0 +1,157 @@
+package csv_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/senseyeio/diligent"
+
+	"github.com/MarioCarrion/versions"
+	"github.com/MarioCarrion/versions/csv"
+	"github.com/MarioCarrion/versions/markdown"
+)
+
+type (
+	failingWriter struct{}
+)
+
+func Test_CSV_Render(t *testing.T) {
+	t.Parallel()
+
+	pkgs := map[versions.ModuleName][]versions.Package{
+		"Module2": {
+			{
+				Name:    "pkg2",
+				Version: "v1.0.0",
+				License: versions.License{
+					Identifier: "MIT",
+					Category:   diligent.Permissive,
+					Status:     versions.LicenseStatusDetected,
+				},
+			},
+			{
+				Name:            "pkg1",
+				Version:         "v1.2.0",
+				ReplacedPath:    "replaced/pkg1",
+				ReplacedVersion: "v1.3.0",
+				License: versions.License{
+					Status: versions.LicenseStatusNotDownloaded,
+				},
+			},
+		},
+		"Module1": {
+			{
+				Name:    "pkg1",
+				Version: "v1.2.0",
+				License: versions.License{
+					Status: versions.LicenseStatusError,
+					Error:  `permission "denied"`,
+				},
+			},
+		},
+	}
+
+	v := versions.Versions{
+		Modules: make(map[versions.ModuleName]versions.Module),
+	}
+
+	for _, name := range []versions.ModuleName{"Module2", "Module1"} {
+		mod := versions.Module{
+			ModuleGoVersion:        versions.ModuleGoVersion{Name: name, GoVersion: "1.15"},
+			DependencyRequirements: make(map[versions.PackageName]versions.Package),
+		}
+
+		v.GoVersions.Set(name, mod.GoVersion)
+
+		for _, pkg := range pkgs[name] {
+			mod.DependencyRequirements[pkg.Name] = pkg
+			v.Packages.Set(name, pkg)
+		}
+
+		v.Modules[name] = mod
+	}
+
+	tests := []struct {
+		name     string
+		input    []csv.Option
+		expected string
+	}{
+		{
+			"OK: as input",
+			nil,
+			"Name,Same,Module2 version,Module2 replacement,Module2 license,Module1 version,Module1 replacement,Module1 license\r\n" +
+				"Go,true,1.15,,,1.15,,\r\n" +
+				"pkg2,true,v1.0.0,,MIT,,,\r\n" +
+				"pkg1,false,v1.2.0,replaced/pkg1 v1.3.0,not-downloaded,v1.2.0,,\"error: permission \"\"denied\"\"\"\r\n",
+		},
+		{
+			"OK: alphabetically",
+			[]csv.Option{
+				csv.WithModulesSorting(markdown.ModulesSortingAlphabetically),
+				csv.WithPackagesSorting(markdown.PackagesSortingAlphabetically),
+			},
+			"Name,Same,Module1 version,Module1 replacement,Module1 license,Module2 version,Module2 replacement,Module2 license\r\n" +
+				"Go,true,1.15,,,1.15,,\r\n" +
+				"pkg1,false,v1.2.0,,\"error: permission \"\"denied\"\"\",v1.2.0,replaced/pkg1 v1.3.0,not-downloaded\r\n" +
+				"pkg2,true,,,,v1.0.0,,MIT\r\n",
+		},
+		{
+			"OK: alphabetically supported, TSV",
+			[]csv.Option{
+				csv.WithFormat(csv.FormatTSV),
+				csv.WithModulesSorting(markdown.ModulesSortingAlphabetically),
+				csv.WithPackagesSorting(markdown.PackagesSortingAlphabeticallySupported),
+			},
+			"Name\tSame\tModule1 version\tModule1 replacement\tModule1 license\tModule2 version\tModule2 replacement\tModule2 license\n" +
+				"Go\ttrue\t1.15\t\t\t1.15\t\t\n" +
+				"pkg2\ttrue\t\t\t\tv1.0.0\t\tMIT\n" +
+				"pkg1\tfalse\tv1.2.0\t\t\"error: permission \"\"denied\"\"\"\tv1.2.0\treplaced/pkg1 v1.3.0\tnot-downloaded\n",
+		},
+		{
+			"OK: coupling",
+			[]csv.Option{
+				csv.WithCoupling(true),
+				csv.WithModulesSorting(markdown.ModulesSortingAlphabetically),
+				csv.WithPackagesSorting(markdown.PackagesSortingAlphabetically),
+			},
+			"Name,Same,Module1 version,Module1 replacement,Module1 license,Module2 version,Module2 replacement,Module2 license,Afferent\r\n" +
+				"Go,true,1.15,,,1.15,,,\r\n" +
+				"Coupling,,\"Ce 1, Ca 0, I 1.00\",,,\"Ce 2, Ca 0, I 1.00\",,,\r\n" +
+				"pkg1,false,v1.2.0,,\"error: permission \"\"denied\"\"\",v1.2.0,replaced/pkg1 v1.3.0,not-downloaded,2\r\n" +
+				"pkg2,true,,,,v1.0.0,,MIT,1\r\n",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := csv.NewCSV(v, test.input...)
+
+			var b strings.Builder
+
+			if err := c.Render(&b); err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+
+			if got := b.String(); got != test.expected {
+				t.Fatalf("expected values do not match: %s", cmp.Diff(got, test.expected))
+			}
+
+			if got := c.String(); got != test.expected {
+				t.Fatalf("expected values do not match: %s", cmp.Diff(got, test.expected))
+			}
+
+			if err := c.Render(failingWriter{}); err == nil {
+				t.Fatalf("expected error, got nil")
+			}
+		})
+	}
+}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("failed")
+}
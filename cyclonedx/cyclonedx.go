@@ -0,0 +1,347 @@
+// Package cyclonedx generates CycloneDX 1.5 software bill of materials, in
+// JSON and XML formats, describing the packages required by a module.
+package cyclonedx
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"golang.org/x/mod/module"
+
+	"github.com/MarioCarrion/versions"
+)
+
+type (
+	// BOM represents a CycloneDX 1.5 bill of materials describing a module.
+	BOM struct {
+		XMLName      xml.Name     `json:"-" xml:"http://cyclonedx.org/schema/bom/1.5 bom"`
+		BOMFormat    string       `json:"bomFormat" xml:"-"`
+		SpecVersion  string       `json:"specVersion" xml:"-"`
+		SerialNumber string       `json:"serialNumber" xml:"serialNumber,attr"`
+		Version      int          `json:"version" xml:"version,attr"`
+		Metadata     Metadata     `json:"metadata" xml:"metadata"`
+		Components   []Component  `json:"components" xml:"components>component"`
+		Dependencies []Dependency `json:"dependencies" xml:"dependencies>dependency"`
+	}
+
+	// Component represents the module described by the BOM, one of its
+	// dependencies or the tool generating it.
+	Component struct {
+		Type     string   `json:"type" xml:"type,attr"`
+		BOMRef   string   `json:"bom-ref,omitempty" xml:"bom-ref,attr,omitempty"`
+		Name     string   `json:"name" xml:"name"`
+		Version  string   `json:"version,omitempty" xml:"version,omitempty"`
+		Scope    string   `json:"scope,omitempty" xml:"scope,omitempty"`
+		Licenses Licenses `json:"licenses,omitempty" xml:"licenses,omitempty"`
+		PURL     string   `json:"purl,omitempty" xml:"purl,omitempty"`
+	}
+
+	// Dependency represents the components a component depends on, referenced
+	// by their BOMRef.
+	Dependency struct {
+		Ref       string   `json:"ref" xml:"ref,attr"`
+		DependsOn []string `json:"dependsOn,omitempty" xml:"-"`
+	}
+
+	// License represents a license using its SPDX identifier, or its name when
+	// it is not a known one.
+	License struct {
+		ID   string `json:"id,omitempty" xml:"id,omitempty"`
+		Name string `json:"name,omitempty" xml:"name,omitempty"`
+	}
+
+	// Licenses represents the licenses of a Component.
+	Licenses []License
+
+	// Metadata represents when, how and what the BOM describes.
+	Metadata struct {
+		Timestamp string    `json:"timestamp" xml:"timestamp"`
+		Tools     Tools     `json:"tools" xml:"tools"`
+		Component Component `json:"component" xml:"component"`
+	}
+
+	// Tools represents the tools used to generate the BOM.
+	Tools struct {
+		Components []Component `json:"components" xml:"components>component"`
+	}
+
+	//-
+
+	// Option is configuration option for NewBOM.
+	Option func(*options)
+
+	//-
+
+	licenseChoice struct {
+		License License `json:"license"`
+	}
+
+	options struct {
+		timestamp    time.Time
+		serialNumber string
+	}
+)
+
+const (
+	// ScopeOptional indicates the component is an indirect requirement.
+	ScopeOptional = "optional"
+
+	// ScopeRequired indicates the component is a direct requirement.
+	ScopeRequired = "required"
+
+	// SpecVersion is the supported CycloneDX version.
+	SpecVersion = "1.5"
+)
+
+const (
+	typeApplication = "application"
+	typeLibrary     = "library"
+)
+
+// NewBOM returns the BOM describing the module and the packages it requires,
+// as library components using their purl as reference; the scope is
+// "required" for direct requirements and "optional" for indirect ones, and
+// the licenses are the detected ones. Packages replaced by modules are
+// described using the replacement.
+//
+// The module depends on all its requirements. When the module graph is known,
+// see versions.WithModuleGraph, the dependencies of each component are
+// included as well, limited to the ones also required by the module.
+func NewBOM(v versions.Versions, name versions.ModuleName, opts ...Option) (BOM, error) {
+	mod, ok := v.Modules[name]
+	if !ok {
+		return BOM{}, fmt.Errorf("unknown module %s", name)
+	}
+
+	o := options{
+		timestamp: time.Now(),
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.serialNumber == "" {
+		serial, err := newSerialNumber()
+		if err != nil {
+			return BOM{}, err
+		}
+
+		o.serialNumber = serial
+	}
+
+	root := Component{
+		Type:   typeApplication,
+		BOMRef: versions.PURL(string(name), ""),
+		Name:   string(name),
+		PURL:   versions.PURL(string(name), ""),
+	}
+
+	bom := BOM{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  SpecVersion,
+		SerialNumber: o.serialNumber,
+		Version:      1,
+		Metadata: Metadata{
+			Timestamp: o.timestamp.UTC().Format(time.RFC3339),
+			Tools: Tools{
+				Components: []Component{{Type: typeApplication, Name: "versions"}},
+			},
+			Component: root,
+		},
+		Components:   []Component{},
+		Dependencies: []Dependency{{Ref: root.BOMRef}},
+	}
+
+	names := make([]versions.PackageName, 0, len(mod.DependencyRequirements))
+	for pkgName := range mod.DependencyRequirements {
+		names = append(names, pkgName)
+	}
+
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+
+	refs := make(map[string]string, len(names))
+
+	for _, pkgName := range names {
+		component := newComponent(mod.DependencyRequirements[pkgName])
+		refs[string(pkgName)] = component.BOMRef
+
+		bom.Components = append(bom.Components, component)
+		bom.Dependencies[0].DependsOn = append(bom.Dependencies[0].DependsOn, component.BOMRef)
+	}
+
+	graph, ok := v.Graphs[name]
+	if !ok {
+		return bom, nil
+	}
+
+	for _, pkgName := range names {
+		dep := Dependency{Ref: refs[string(pkgName)]}
+
+		version := module.Version{Path: string(pkgName), Version: graph.Selected(pkgName)}
+
+		for _, req := range graph.Requirements(version) {
+			if ref, ok := refs[req.Path]; ok {
+				dep.DependsOn = append(dep.DependsOn, ref)
+			}
+		}
+
+		sort.Strings(dep.DependsOn)
+
+		bom.Dependencies = append(bom.Dependencies, dep)
+	}
+
+	return bom, nil
+}
+
+// WithSerialNumber allows specifying the unique URN of the BOM, it defaults to
+// a random UUID one.
+func WithSerialNumber(serial string) Option {
+	return func(o *options) {
+		o.serialNumber = serial
+	}
+}
+
+// WithTimestamp allows specifying the creation time of the BOM, it defaults to
+// the current time.
+func WithTimestamp(t time.Time) Option {
+	return func(o *options) {
+		o.timestamp = t
+	}
+}
+
+func newComponent(pkg versions.Package) Component {
+	res := Component{
+		Type:    typeLibrary,
+		BOMRef:  pkg.PURL(),
+		Name:    string(pkg.Name),
+		Version: pkg.BuiltVersion(),
+		Scope:   ScopeRequired,
+		PURL:    pkg.PURL(),
+	}
+
+	if res.BOMRef == "" { // local directory replacements are not described by a purl
+		res.BOMRef = string(pkg.Name)
+	}
+
+	if pkg.IsIndirect {
+		res.Scope = ScopeOptional
+	}
+
+	switch pkg.License.Status {
+	case versions.LicenseStatusDetected:
+		if pkg.License.Identifier != "" {
+			res.Licenses = Licenses{{ID: pkg.License.Identifier}}
+		}
+	case versions.LicenseStatusUnrecognized:
+		res.Licenses = Licenses{{Name: pkg.License.Identifier}}
+	}
+
+	return res
+}
+
+// newSerialNumber returns a random, version 4, UUID URN.
+func newSerialNumber() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+// RenderJSON writes the BOM in JSON format to w.
+func (b BOM) RenderJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(b)
+}
+
+// RenderXML writes the BOM in XML format to w.
+func (b BOM) RenderXML(w io.Writer) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	if err := enc.Encode(b); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
+
+// MarshalXML encodes the dependency including the nested dependencies it
+// depends on.
+func (d Dependency) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "ref"}, Value: d.Ref})
+
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+
+	for _, ref := range d.DependsOn {
+		child := xml.StartElement{
+			Name: xml.Name{Local: "dependency"},
+			Attr: []xml.Attr{{Name: xml.Name{Local: "ref"}, Value: ref}},
+		}
+
+		if err := e.EncodeToken(child); err != nil {
+			return err
+		}
+
+		if err := e.EncodeToken(child.End()); err != nil {
+			return err
+		}
+	}
+
+	return e.EncodeToken(start.End())
+}
+
+// MarshalJSON encodes the licenses as license choices.
+func (l Licenses) MarshalJSON() ([]byte, error) {
+	res := make([]licenseChoice, len(l))
+
+	for i, license := range l {
+		res[i] = licenseChoice{License: license}
+	}
+
+	return json.Marshal(res)
+}
+
+// MarshalXML encodes the licenses as license choices.
+func (l Licenses) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(struct {
+		License []License `xml:"license"`
+	}{l}, start)
+}
+
+// UnmarshalJSON decodes the licenses from license choices.
+func (l *Licenses) UnmarshalJSON(data []byte) error {
+	var choices []licenseChoice
+
+	if err := json.Unmarshal(data, &choices); err != nil {
+		return err
+	}
+
+	*l = make(Licenses, len(choices))
+
+	for i, choice := range choices {
+		(*l)[i] = choice.License
+	}
+
+	return nil
+}
@@ -0,0 +1,238 @@
+package cyclonedx_test
+
+import (
+	"encoding/json"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/xeipuuv/gojsonschema"
+
+	"github.com/MarioCarrion/versions/cyclonedx"
+	"github.com/MarioCarrion/versions/internal/sbomtest"
+)
+
+func Test_BOM_RenderJSON(t *testing.T) {
+	t.Parallel()
+
+	bom, err := cyclonedx.NewBOM(sbomtest.NewVersions(), sbomtest.Module)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	var b strings.Builder
+
+	if err := bom.RenderJSON(&b); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	// The official schema references the SPDX and JSF ones by their URL.
+	loader := gojsonschema.NewSchemaLoader()
+
+	if err := loader.AddSchemas(
+		gojsonschema.NewReferenceLoader("file://"+fixture(t, "spdx.schema.json")),
+		gojsonschema.NewReferenceLoader("file://"+fixture(t, "jsf-0.82.schema.json")),
+	); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	schema, err := loader.Compile(gojsonschema.NewReferenceLoader("file://" + fixture(t, "bom-1.5.schema.json")))
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	res, err := schema.Validate(gojsonschema.NewStringLoader(b.String()))
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if !res.Valid() {
+		t.Fatalf("expected valid BOM, got %v", res.Errors())
+	}
+
+	var got cyclonedx.BOM
+
+	if err := json.Unmarshal([]byte(b.String()), &got); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if !cmp.Equal(got, bom) {
+		t.Fatalf("expected values do not match: %s", cmp.Diff(got, bom))
+	}
+
+	for _, expected := range []string{
+		`"bomFormat": "CycloneDX"`,
+		`"specVersion": "1.5"`,
+		`"licenses": [` + "\n" + `        {` + "\n" + `          "license": {` + "\n" + `            "id": "MIT"`,
+		`"scope": "optional"`,
+	} {
+		if !strings.Contains(b.String(), expected) {
+			t.Fatalf("expected %s in %s", expected, b.String())
+		}
+	}
+}
+
+func Test_BOM_RenderXML(t *testing.T) {
+	t.Parallel()
+
+	bom, err := cyclonedx.NewBOM(sbomtest.NewVersions(), sbomtest.Module,
+		cyclonedx.WithSerialNumber("urn:uuid:3e671687-395b-41f5-a30f-a58921a69b79"),
+		cyclonedx.WithTimestamp(time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("", 3600))))
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<bom xmlns="http://cyclonedx.org/schema/bom/1.5" serialNumber="urn:uuid:3e671687-395b-41f5-a30f-a58921a69b79" version="1">
+  <metadata>
+    <timestamp>2024-01-02T02:04:05Z</timestamp>
+    <tools>
+      <components>
+        <component type="application">
+          <name>versions</name>
+        </component>
+      </components>
+    </tools>
+    <component type="application" bom-ref="pkg:golang/fixture.com/module">
+      <name>fixture.com/module</name>
+      <purl>pkg:golang/fixture.com/module</purl>
+    </component>
+  </metadata>
+  <components>
+    <component type="library" bom-ref="pkg:golang/github.com/Example/incompatible@v2.0.0%2Bincompatible">
+      <name>github.com/Example/incompatible</name>
+      <version>v2.0.0+incompatible</version>
+      <scope>required</scope>
+      <licenses>
+        <license>
+          <id>MIT</id>
+        </license>
+      </licenses>
+      <purl>pkg:golang/github.com/Example/incompatible@v2.0.0%2Bincompatible</purl>
+    </component>
+    <component type="library" bom-ref="pkg:golang/github.com/example/indirect@v1.1.0">
+      <name>github.com/example/indirect</name>
+      <version>v1.1.0</version>
+      <scope>optional</scope>
+      <purl>pkg:golang/github.com/example/indirect@v1.1.0</purl>
+    </component>
+    <component type="library" bom-ref="github.com/example/local">
+      <name>github.com/example/local</name>
+      <scope>required</scope>
+    </component>
+    <component type="library" bom-ref="pkg:golang/github.com/fork/replaced@v1.5.0">
+      <name>github.com/example/replaced</name>
+      <version>v1.5.0</version>
+      <scope>required</scope>
+      <licenses>
+        <license>
+          <name>Beerware</name>
+        </license>
+      </licenses>
+      <purl>pkg:golang/github.com/fork/replaced@v1.5.0</purl>
+    </component>
+  </components>
+  <dependencies>
+    <dependency ref="pkg:golang/fixture.com/module">
+      <dependency ref="pkg:golang/github.com/Example/incompatible@v2.0.0%2Bincompatible"></dependency>
+      <dependency ref="pkg:golang/github.com/example/indirect@v1.1.0"></dependency>
+      <dependency ref="github.com/example/local"></dependency>
+      <dependency ref="pkg:golang/github.com/fork/replaced@v1.5.0"></dependency>
+    </dependency>
+  </dependencies>
+</bom>
+`
+
+	var b strings.Builder
+
+	if err := bom.RenderXML(&b); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if got := b.String(); got != expected {
+		t.Fatalf("expected values do not match: %s", cmp.Diff(got, expected))
+	}
+
+	xmllint, err := exec.LookPath("xmllint")
+	if err != nil {
+		t.Fatalf("expected xmllint to validate the BOM, got %s", err)
+	}
+
+	cmd := exec.Command(xmllint, "--noout", "--schema", fixture(t, "bom-1.5.xsd"), "-")
+	cmd.Stdin = strings.NewReader(b.String())
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("expected valid BOM, got %s: %s", err, out)
+	}
+}
+
+func Test_NewBOM(t *testing.T) {
+	t.Parallel()
+
+	if _, err := cyclonedx.NewBOM(sbomtest.NewVersions(), "fixture.com/unknown"); err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+
+	first, err := cyclonedx.NewBOM(sbomtest.NewVersions(), sbomtest.Module)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	second, err := cyclonedx.NewBOM(sbomtest.NewVersions(), sbomtest.Module)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	uuid := regexp.MustCompile(`^urn:uuid:[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	if !uuid.MatchString(first.SerialNumber) || first.SerialNumber == second.SerialNumber {
+		t.Fatalf("expected unique serial numbers, got %s and %s", first.SerialNumber, second.SerialNumber)
+	}
+}
+
+func Test_NewBOM_ModuleGraph(t *testing.T) {
+	bom, err := cyclonedx.NewBOM(sbomtest.NewModuleGraph(t), sbomtest.ModuleGraph)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	expected := []cyclonedx.Dependency{
+		{
+			Ref: "pkg:golang/fixture.com/sbom",
+			DependsOn: []string{
+				"pkg:golang/example.com/a@v1.0.0",
+				"pkg:golang/example.com/c@v1.2.0",
+				"pkg:golang/example.com/d@v1.0.0",
+			},
+		},
+		{
+			Ref:       "pkg:golang/example.com/a@v1.0.0",
+			DependsOn: []string{"pkg:golang/example.com/c@v1.2.0"},
+		},
+		{
+			Ref: "pkg:golang/example.com/c@v1.2.0",
+		},
+		{
+			Ref: "pkg:golang/example.com/d@v1.0.0",
+		},
+	}
+
+	if !cmp.Equal(bom.Dependencies, expected) {
+		t.Fatalf("expected values do not match: %s", cmp.Diff(bom.Dependencies, expected))
+	}
+}
+
+func fixture(t *testing.T, name string) string {
+	t.Helper()
+
+	res, err := filepath.Abs(filepath.Join("..", "fixtures", "cyclonedx", name))
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	return res
+}
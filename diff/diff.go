@@ -0,0 +1,374 @@
+// Package diff reports the changes between two snapshots of the same set of
+// modules, in Markdown and JSON formats, to be used as release notes.
+package diff
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/semver"
+
+	"github.com/MarioCarrion/versions"
+)
+
+type (
+	// LicenseChange represents a package using a different license.
+	LicenseChange struct {
+		Name versions.PackageName `json:"name"`
+		From string               `json:"from"`
+		To   string               `json:"to"`
+	}
+
+	// Module represents the changes of a module found in both snapshots.
+	Module struct {
+		Name          versions.ModuleName `json:"name"`
+		FromGoVersion versions.GoVersion  `json:"fromGoVersion"`
+		ToGoVersion   versions.GoVersion  `json:"toGoVersion"`
+		Added         []Package           `json:"added"`
+		Removed       []Package           `json:"removed"`
+		Upgraded      []VersionChange     `json:"upgraded"`
+		Downgraded    []VersionChange     `json:"downgraded"`
+		Replacements  []ReplacementChange `json:"replacements"`
+		Licenses      []LicenseChange     `json:"licenses"`
+	}
+
+	// Package represents a package added or removed from a module, License is
+	// empty when unknown and Replacement when not replaced.
+	Package struct {
+		Name        versions.PackageName `json:"name"`
+		Version     string               `json:"version"`
+		Replacement string               `json:"replacement"`
+		License     string               `json:"license"`
+	}
+
+	// ReplacementChange represents a package replaced using a different path
+	// or version, From or To are empty when not replaced.
+	ReplacementChange struct {
+		Name versions.PackageName `json:"name"`
+		From string               `json:"from"`
+		To   string               `json:"to"`
+	}
+
+	// Report represents the changes between two snapshots, only modules with
+	// changes are included in Modules.
+	Report struct {
+		Added   []versions.ModuleName `json:"added"`
+		Removed []versions.ModuleName `json:"removed"`
+		Modules []Module              `json:"modules"`
+	}
+
+	// VersionChange represents a package required using a different version,
+	// Drift is one of the versions.Drift values.
+	VersionChange struct {
+		Name  versions.PackageName `json:"name"`
+		From  string               `json:"from"`
+		To    string               `json:"to"`
+		Drift versions.Drift       `json:"drift"`
+	}
+)
+
+var (
+	// ErrSelectionMismatch indicates only one of the snapshots includes the
+	// versions selected by Minimal Version Selection.
+	ErrSelectionMismatch = errors.New("only one snapshot includes the versions selected by Minimal Version Selection")
+)
+
+// Compare returns the changes between the from and to snapshots, modules and
+// packages are sorted alphabetically by their name.
+//
+// Packages are compared using the version selected by Minimal Version
+// Selection, when known, otherwise the required one; both snapshots must be
+// generated the same way, otherwise ErrSelectionMismatch is returned.
+// Replacements are compared using their path and version. License changes are
+// only reported when the licenses of both versions are known: detected,
+// unrecognized or not found; licenses skipped, not downloaded or failing to
+// be detected are unknown.
+func Compare(from, to versions.Versions) (Report, error) {
+	fromSelected, fromOK := selected(from)
+	toSelected, toOK := selected(to)
+
+	if fromOK && toOK && fromSelected != toSelected {
+		return Report{}, ErrSelectionMismatch
+	}
+
+	res := Report{
+		Added:   []versions.ModuleName{},
+		Removed: []versions.ModuleName{},
+		Modules: []Module{},
+	}
+
+	for _, name := range moduleNames(from, to) {
+		fromMod, inFrom := from.Modules[name]
+		toMod, inTo := to.Modules[name]
+
+		switch {
+		case !inFrom:
+			res.Added = append(res.Added, name)
+		case !inTo:
+			res.Removed = append(res.Removed, name)
+		default:
+			if mod := compareModules(fromMod, toMod); mod.hasChanges() {
+				res.Modules = append(res.Modules, mod)
+			}
+		}
+	}
+
+	return res, nil
+}
+
+func compareModules(from, to versions.Module) Module {
+	res := Module{
+		Name:          to.Name,
+		FromGoVersion: from.GoVersion,
+		ToGoVersion:   to.GoVersion,
+		Added:         []Package{},
+		Removed:       []Package{},
+		Upgraded:      []VersionChange{},
+		Downgraded:    []VersionChange{},
+		Replacements:  []ReplacementChange{},
+		Licenses:      []LicenseChange{},
+	}
+
+	names := make([]versions.PackageName, 0, len(from.DependencyRequirements)+len(to.DependencyRequirements))
+
+	for name := range from.DependencyRequirements {
+		names = append(names, name)
+	}
+
+	for name := range to.DependencyRequirements {
+		if _, ok := from.DependencyRequirements[name]; !ok {
+			names = append(names, name)
+		}
+	}
+
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+
+	for _, name := range names {
+		fromPkg, inFrom := from.DependencyRequirements[name]
+		toPkg, inTo := to.DependencyRequirements[name]
+
+		if !inFrom {
+			res.Added = append(res.Added, newPackage(toPkg))
+			continue
+		}
+
+		if !inTo {
+			res.Removed = append(res.Removed, newPackage(fromPkg))
+			continue
+		}
+
+		if change := newVersionChange(fromPkg, toPkg); change.Drift != versions.DriftNone {
+			if semver.Compare(change.To, change.From) < 0 {
+				res.Downgraded = append(res.Downgraded, change)
+			} else {
+				res.Upgraded = append(res.Upgraded, change)
+			}
+		}
+
+		if fromReplacement, toReplacement := replacement(fromPkg), replacement(toPkg); fromReplacement != toReplacement {
+			res.Replacements = append(res.Replacements, ReplacementChange{Name: name, From: fromReplacement, To: toReplacement})
+		}
+
+		fromLicense, toLicense := license(fromPkg.License), license(toPkg.License)
+		if fromLicense != "" && toLicense != "" && fromLicense != toLicense {
+			res.Licenses = append(res.Licenses, LicenseChange{Name: name, From: fromLicense, To: toLicense})
+		}
+	}
+
+	return res
+}
+
+// license returns the SPDX identifier of the license, the status is used
+// when it was not detected, and an empty string when it is unknown.
+func license(l versions.License) string {
+	switch l.Status {
+	case versions.LicenseStatusDetected:
+		return l.Identifier
+	case versions.LicenseStatusUnrecognized:
+		return fmt.Sprintf("%s %s", l.Status, l.Identifier)
+	case versions.LicenseStatusNotFound:
+		return string(l.Status)
+	}
+
+	return ""
+}
+
+// licenseSuffix returns the license to be appended to a Markdown item, if
+// known.
+func licenseSuffix(license string) string {
+	if license == "" {
+		return ""
+	}
+
+	return fmt.Sprintf(" (%s)", license)
+}
+
+// moduleNames returns the sorted names of the modules in both snapshots.
+func moduleNames(from, to versions.Versions) []versions.ModuleName {
+	res := make([]versions.ModuleName, 0, len(from.Modules)+len(to.Modules))
+
+	for name := range from.Modules {
+		res = append(res, name)
+	}
+
+	for name := range to.Modules {
+		if _, ok := from.Modules[name]; !ok {
+			res = append(res, name)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+
+	return res
+}
+
+func newPackage(pkg versions.Package) Package {
+	return Package{
+		Name:        pkg.Name,
+		Version:     pkg.EffectiveVersion(),
+		Replacement: replacement(pkg),
+		License:     license(pkg.License),
+	}
+}
+
+func newVersionChange(from, to versions.Package) VersionChange {
+	return VersionChange{
+		Name:  to.Name,
+		From:  from.EffectiveVersion(),
+		To:    to.EffectiveVersion(),
+		Drift: versions.VersionDrift(from.EffectiveVersion(), to.EffectiveVersion()),
+	}
+}
+
+// replacement returns the path and version replacing the package, if any.
+func replacement(pkg versions.Package) string {
+	return strings.TrimSpace(pkg.ReplacedPath + " " + pkg.ReplacedVersion)
+}
+
+// replacementItem returns the replacement quoted for a Markdown item, or
+// "none" when not replaced.
+func replacementItem(replacement string) string {
+	if replacement == "" {
+		return "none"
+	}
+
+	return fmt.Sprintf("`%s`", replacement)
+}
+
+// replacementSuffix returns the replacement to be appended to a Markdown
+// item, if any.
+func replacementSuffix(replacement string) string {
+	if replacement == "" {
+		return ""
+	}
+
+	return fmt.Sprintf(" => `%s`", replacement)
+}
+
+// selected returns true when the packages include the versions selected by
+// Minimal Version Selection, ok is false when there are no packages.
+func selected(v versions.Versions) (selected, ok bool) {
+	for _, mod := range v.Modules {
+		for _, pkg := range mod.DependencyRequirements {
+			if pkg.SelectedVersion != "" {
+				return true, true
+			}
+
+			ok = true
+		}
+	}
+
+	return false, ok
+}
+
+// writeModules writes a Markdown section listing the modules, if any.
+func writeModules(b *strings.Builder, title string, names []versions.ModuleName) {
+	if len(names) == 0 {
+		return
+	}
+
+	fmt.Fprintf(b, "\n## %s\n\n", title)
+
+	for _, name := range names {
+		fmt.Fprintf(b, "* `%s`\n", name)
+	}
+}
+
+// hasChanges returns true when any of the packages or the Go version changed.
+func (m Module) hasChanges() bool {
+	return m.FromGoVersion != m.ToGoVersion ||
+		len(m.Added) > 0 ||
+		len(m.Removed) > 0 ||
+		len(m.Upgraded) > 0 ||
+		len(m.Downgraded) > 0 ||
+		len(m.Replacements) > 0 ||
+		len(m.Licenses) > 0
+}
+
+// IsEmpty returns true when there are no changes.
+func (r Report) IsEmpty() bool {
+	return len(r.Added) == 0 && len(r.Removed) == 0 && len(r.Modules) == 0
+}
+
+// RenderJSON writes the report in JSON format to w.
+func (r Report) RenderJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(r)
+}
+
+// RenderMarkdown writes the report in Markdown format to w, each module with
+// changes is a section listing them.
+func (r Report) RenderMarkdown(w io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString("# Changes\n")
+
+	if r.IsEmpty() {
+		b.WriteString("\nNo changes.\n")
+	}
+
+	writeModules(&b, "Added modules", r.Added)
+	writeModules(&b, "Removed modules", r.Removed)
+
+	for _, mod := range r.Modules {
+		fmt.Fprintf(&b, "\n## %s\n\n", mod.Name)
+
+		if mod.FromGoVersion != mod.ToGoVersion {
+			fmt.Fprintf(&b, "* Go version: `%s` => `%s`\n", mod.FromGoVersion, mod.ToGoVersion)
+		}
+
+		for _, pkg := range mod.Added {
+			fmt.Fprintf(&b, "* Added `%s` `%s`%s%s\n", pkg.Name, pkg.Version, replacementSuffix(pkg.Replacement), licenseSuffix(pkg.License))
+		}
+
+		for _, pkg := range mod.Removed {
+			fmt.Fprintf(&b, "* Removed `%s` `%s`%s%s\n", pkg.Name, pkg.Version, replacementSuffix(pkg.Replacement), licenseSuffix(pkg.License))
+		}
+
+		for _, change := range mod.Upgraded {
+			fmt.Fprintf(&b, "* Upgraded `%s` `%s` => `%s` (%s)\n", change.Name, change.From, change.To, change.Drift)
+		}
+
+		for _, change := range mod.Downgraded {
+			fmt.Fprintf(&b, "* Downgraded `%s` `%s` => `%s` (%s)\n", change.Name, change.From, change.To, change.Drift)
+		}
+
+		for _, change := range mod.Replacements {
+			fmt.Fprintf(&b, "* Replacement of `%s`: %s => %s\n", change.Name, replacementItem(change.From), replacementItem(change.To))
+		}
+
+		for _, change := range mod.Licenses {
+			fmt.Fprintf(&b, "* License of `%s`: `%s` => `%s`\n", change.Name, change.From, change.To)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
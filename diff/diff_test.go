@@ -0,0 +1,389 @@
+package diff_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/MarioCarrion/versions"
+	"github.com/MarioCarrion/versions/diff"
+)
+
+func Test_Compare(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		from     versions.Versions
+		to       versions.Versions
+		expected diff.Report
+		err      bool
+	}{
+		{
+			"OK: no changes",
+			newFrom(),
+			newFrom(),
+			diff.Report{
+				Added:   []versions.ModuleName{},
+				Removed: []versions.ModuleName{},
+				Modules: []diff.Module{},
+			},
+			false,
+		},
+		{
+			"OK: changes",
+			newFrom(),
+			newTo(),
+			newReport(),
+			false,
+		},
+		{
+			"OK: reversed",
+			newTo(),
+			newFrom(),
+			newReversedReport(),
+			false,
+		},
+		{
+			"OK: minimal version selection",
+			newVersions(map[versions.ModuleName]versions.GoVersion{"fixture.com/module": "1.15"}, []versions.Package{
+				{Name: "example.com/upgraded", Version: "v1.0.0", SelectedVersion: "v1.0.0"},
+			}),
+			newVersions(map[versions.ModuleName]versions.GoVersion{"fixture.com/module": "1.15"}, []versions.Package{
+				{Name: "example.com/upgraded", Version: "v1.0.0", SelectedVersion: "v1.5.0"},
+			}),
+			diff.Report{
+				Added:   []versions.ModuleName{},
+				Removed: []versions.ModuleName{},
+				Modules: []diff.Module{
+					{
+						Name:          "fixture.com/module",
+						FromGoVersion: "1.15",
+						ToGoVersion:   "1.15",
+						Added:         []diff.Package{},
+						Removed:       []diff.Package{},
+						Upgraded: []diff.VersionChange{
+							{Name: "example.com/upgraded", From: "v1.0.0", To: "v1.5.0", Drift: versions.DriftMinor},
+						},
+						Downgraded:   []diff.VersionChange{},
+						Replacements: []diff.ReplacementChange{},
+						Licenses:     []diff.LicenseChange{},
+					},
+				},
+			},
+			false,
+		},
+		{
+			"ERR: minimal version selection in one snapshot",
+			newFrom(),
+			newVersions(map[versions.ModuleName]versions.GoVersion{"fixture.com/module": "1.15"}, []versions.Package{
+				{Name: "example.com/upgraded", Version: "v1.0.0", SelectedVersion: "v1.5.0"},
+			}),
+			diff.Report{},
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := diff.Compare(test.from, test.to)
+			if (err != nil) != test.err {
+				t.Fatalf("expected error %t, got %v", test.err, err)
+			}
+
+			if !cmp.Equal(got, test.expected) {
+				t.Fatalf("expected values do not match: %s", cmp.Diff(got, test.expected))
+			}
+		})
+	}
+}
+
+func Test_Report_RenderJSON(t *testing.T) {
+	t.Parallel()
+
+	var b strings.Builder
+
+	if err := newReport().RenderJSON(&b); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	var got diff.Report
+
+	if err := json.Unmarshal([]byte(b.String()), &got); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if !cmp.Equal(got, newReport()) {
+		t.Fatalf("expected values do not match: %s", cmp.Diff(got, newReport()))
+	}
+
+	for _, expected := range []string{`"fromGoVersion": "1.15"`, `"drift": "minor"`} {
+		if !strings.Contains(b.String(), expected) {
+			t.Fatalf("expected %s in %s", expected, b.String())
+		}
+	}
+}
+
+func Test_Report_RenderMarkdown(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    diff.Report
+		expected string
+	}{
+		{
+			"OK: no changes",
+			diff.Report{},
+			"# Changes\n" +
+				"\n" +
+				"No changes.\n",
+		},
+		{
+			"OK: changes",
+			newReport(),
+			"# Changes\n" +
+				"\n" +
+				"## Added modules\n" +
+				"\n" +
+				"* `fixture.com/added`\n" +
+				"\n" +
+				"## Removed modules\n" +
+				"\n" +
+				"* `fixture.com/removed`\n" +
+				"\n" +
+				"## fixture.com/module\n" +
+				"\n" +
+				"* Go version: `1.15` => `1.16`\n" +
+				"* Added `example.com/added` `v0.1.0` (MIT)\n" +
+				"* Removed `example.com/removed` `v1.0.0` => `../removed`\n" +
+				"* Upgraded `example.com/upgraded` `v1.0.0` => `v1.5.0` (minor)\n" +
+				"* Downgraded `example.com/downgraded` `v1.2.1` => `v1.2.0` (patch)\n" +
+				"* Replacement of `example.com/replaced`: `../replaced` => `example.com/fork v1.0.1`\n" +
+				"* Replacement of `example.com/unreplaced`: `example.com/fork v1.0.0` => none\n" +
+				"* License of `example.com/relicensed`: `MIT` => `BSD-3-Clause`\n",
+		},
+		{
+			"OK: reversed",
+			newReversedReport(),
+			"# Changes\n" +
+				"\n" +
+				"## Added modules\n" +
+				"\n" +
+				"* `fixture.com/removed`\n" +
+				"\n" +
+				"## Removed modules\n" +
+				"\n" +
+				"* `fixture.com/added`\n" +
+				"\n" +
+				"## fixture.com/module\n" +
+				"\n" +
+				"* Go version: `1.16` => `1.15`\n" +
+				"* Added `example.com/removed` `v1.0.0` => `../removed`\n" +
+				"* Removed `example.com/added` `v0.1.0` (MIT)\n" +
+				"* Upgraded `example.com/downgraded` `v1.2.0` => `v1.2.1` (patch)\n" +
+				"* Downgraded `example.com/upgraded` `v1.5.0` => `v1.0.0` (minor)\n" +
+				"* Replacement of `example.com/replaced`: `example.com/fork v1.0.1` => `../replaced`\n" +
+				"* Replacement of `example.com/unreplaced`: none => `example.com/fork v1.0.0`\n" +
+				"* License of `example.com/relicensed`: `BSD-3-Clause` => `MIT`\n",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var b strings.Builder
+
+			if err := test.input.RenderMarkdown(&b); err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+
+			if got := b.String(); got != test.expected {
+				t.Fatalf("expected values do not match: %s", cmp.Diff(got, test.expected))
+			}
+		})
+	}
+}
+
+func newFrom() versions.Versions {
+	return newVersions(map[versions.ModuleName]versions.GoVersion{
+		"fixture.com/module":  "1.15",
+		"fixture.com/removed": "1.15",
+	}, []versions.Package{
+		{
+			Name:    "example.com/downgraded",
+			Version: "v1.2.1",
+		},
+		{
+			Name:    "example.com/relicensed",
+			Version: "v1.0.0",
+			License: versions.License{Identifier: "MIT", Status: versions.LicenseStatusDetected},
+		},
+		{
+			Name:         "example.com/removed",
+			Version:      "v1.0.0",
+			ReplacedPath: "../removed",
+		},
+		{
+			Name:         "example.com/replaced",
+			Version:      "v1.0.0",
+			ReplacedPath: "../replaced",
+		},
+		{
+			Name:    "example.com/unknown",
+			Version: "v1.0.0",
+			License: versions.License{Status: versions.LicenseStatusNotDownloaded},
+		},
+		{
+			Name:            "example.com/unreplaced",
+			Version:         "v1.0.0",
+			ReplacedPath:    "example.com/fork",
+			ReplacedVersion: "v1.0.0",
+		},
+		{
+			Name:    "example.com/upgraded",
+			Version: "v1.0.0",
+		},
+	})
+}
+
+func newReport() diff.Report {
+	return diff.Report{
+		Added:   []versions.ModuleName{"fixture.com/added"},
+		Removed: []versions.ModuleName{"fixture.com/removed"},
+		Modules: []diff.Module{
+			{
+				Name:          "fixture.com/module",
+				FromGoVersion: "1.15",
+				ToGoVersion:   "1.16",
+				Added: []diff.Package{
+					{Name: "example.com/added", Version: "v0.1.0", License: "MIT"},
+				},
+				Removed: []diff.Package{
+					{Name: "example.com/removed", Version: "v1.0.0", Replacement: "../removed"},
+				},
+				Upgraded: []diff.VersionChange{
+					{Name: "example.com/upgraded", From: "v1.0.0", To: "v1.5.0", Drift: versions.DriftMinor},
+				},
+				Downgraded: []diff.VersionChange{
+					{Name: "example.com/downgraded", From: "v1.2.1", To: "v1.2.0", Drift: versions.DriftPatch},
+				},
+				Replacements: []diff.ReplacementChange{
+					{Name: "example.com/replaced", From: "../replaced", To: "example.com/fork v1.0.1"},
+					{Name: "example.com/unreplaced", From: "example.com/fork v1.0.0"},
+				},
+				Licenses: []diff.LicenseChange{
+					{Name: "example.com/relicensed", From: "MIT", To: "BSD-3-Clause"},
+				},
+			},
+		},
+	}
+}
+
+func newReversedReport() diff.Report {
+	return diff.Report{
+		Added:   []versions.ModuleName{"fixture.com/removed"},
+		Removed: []versions.ModuleName{"fixture.com/added"},
+		Modules: []diff.Module{
+			{
+				Name:          "fixture.com/module",
+				FromGoVersion: "1.16",
+				ToGoVersion:   "1.15",
+				Added: []diff.Package{
+					{Name: "example.com/removed", Version: "v1.0.0", Replacement: "../removed"},
+				},
+				Removed: []diff.Package{
+					{Name: "example.com/added", Version: "v0.1.0", License: "MIT"},
+				},
+				Upgraded: []diff.VersionChange{
+					{Name: "example.com/downgraded", From: "v1.2.0", To: "v1.2.1", Drift: versions.DriftPatch},
+				},
+				Downgraded: []diff.VersionChange{
+					{Name: "example.com/upgraded", From: "v1.5.0", To: "v1.0.0", Drift: versions.DriftMinor},
+				},
+				Replacements: []diff.ReplacementChange{
+					{Name: "example.com/replaced", From: "example.com/fork v1.0.1", To: "../replaced"},
+					{Name: "example.com/unreplaced", To: "example.com/fork v1.0.0"},
+				},
+				Licenses: []diff.LicenseChange{
+					{Name: "example.com/relicensed", From: "BSD-3-Clause", To: "MIT"},
+				},
+			},
+		},
+	}
+}
+
+func newTo() versions.Versions {
+	return newVersions(map[versions.ModuleName]versions.GoVersion{
+		"fixture.com/added":  "1.16",
+		"fixture.com/module": "1.16",
+	}, []versions.Package{
+		{
+			Name:    "example.com/added",
+			Version: "v0.1.0",
+			License: versions.License{Identifier: "MIT", Status: versions.LicenseStatusDetected},
+		},
+		{
+			Name:    "example.com/downgraded",
+			Version: "v1.2.0",
+		},
+		{
+			Name:    "example.com/relicensed",
+			Version: "v1.0.0",
+			License: versions.License{Identifier: "BSD-3-Clause", Status: versions.LicenseStatusDetected},
+		},
+		{
+			Name:            "example.com/replaced",
+			Version:         "v1.0.0",
+			ReplacedPath:    "example.com/fork",
+			ReplacedVersion: "v1.0.1",
+		},
+		{
+			Name:    "example.com/unknown",
+			Version: "v1.0.0",
+			License: versions.License{Identifier: "MIT", Status: versions.LicenseStatusDetected},
+		},
+		{
+			Name:    "example.com/unreplaced",
+			Version: "v1.0.0",
+		},
+		{
+			Name:    "example.com/upgraded",
+			Version: "v1.5.0",
+		},
+	})
+}
+
+// newVersions returns the modules, using the Go versions, requiring the same
+// packages.
+func newVersions(goVersions map[versions.ModuleName]versions.GoVersion, pkgs []versions.Package) versions.Versions {
+	res := versions.Versions{
+		Modules: make(map[versions.ModuleName]versions.Module),
+	}
+
+	for name, goVersion := range goVersions {
+		mod := versions.Module{
+			ModuleGoVersion:        versions.ModuleGoVersion{Name: name, GoVersion: goVersion},
+			DependencyRequirements: make(map[versions.PackageName]versions.Package),
+		}
+
+		for _, pkg := range pkgs {
+			mod.DependencyRequirements[pkg.Name] = pkg
+			res.Packages.Set(name, pkg)
+		}
+
+		res.Modules[name] = mod
+		res.GoVersions.Set(name, goVersion)
+	}
+
+	return res
+}
@@ -0,0 +1,212 @@
+package versions
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+type (
+	excludePattern struct {
+		segments []string
+		negate   bool
+		dirOnly  bool
+		anchored bool
+	}
+
+	excludePatterns []excludePattern
+)
+
+// Discover walks the root directories and returns the paths to all the go.mod
+// files found.
+//
+// Directories named "vendor" or "testdata", as well as the ones starting with
+// "." or "_", are skipped. Files and directories matching any of the
+// .gitignore-style exclude patterns, or the patterns defined in the .gitignore
+// file located in each root directory, are skipped as well.
+func Discover(roots []string, excludes []string) ([]string, error) {
+	var res []string
+
+	for _, root := range roots {
+		patterns := newExcludePatterns(excludes)
+
+		gitignore, err := readExcludePatterns(filepath.Join(root, ".gitignore"))
+		if err != nil {
+			return nil, err
+		}
+
+		patterns = append(patterns, gitignore...)
+
+		err = filepath.Walk(root, func(file string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			rel, err := filepath.Rel(root, file)
+			if err != nil {
+				return err
+			}
+
+			if rel == "." {
+				return nil
+			}
+
+			rel = filepath.ToSlash(rel)
+
+			if info.IsDir() {
+				name := info.Name()
+				if name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+					return filepath.SkipDir
+				}
+
+				if patterns.Match(rel, true) {
+					return filepath.SkipDir
+				}
+
+				return nil
+			}
+
+			if info.Name() == "go.mod" && !patterns.Match(rel, false) {
+				res = append(res, file)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+func matchSegments(pattern, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+
+	if pattern[0] == "**" {
+		if len(pattern) == 1 { // trailing "**" matches everything inside
+			return len(name) > 0
+		}
+
+		for i := 0; i <= len(name); i++ {
+			if matchSegments(pattern[1:], name[i:]) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if len(name) == 0 {
+		return false
+	}
+
+	if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+		return false
+	}
+
+	return matchSegments(pattern[1:], name[1:])
+}
+
+func newExcludePattern(line string) (excludePattern, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return excludePattern{}, false
+	}
+
+	var res excludePattern
+
+	if strings.HasPrefix(line, "!") {
+		res.negate = true
+		line = line[1:]
+	}
+
+	if strings.HasSuffix(line, "/") {
+		res.dirOnly = true
+		line = strings.TrimSuffix(line, "/")
+	}
+
+	if strings.Contains(line, "/") {
+		res.anchored = true
+		line = strings.TrimPrefix(line, "/")
+	}
+
+	if line == "" {
+		return excludePattern{}, false
+	}
+
+	res.segments = strings.Split(line, "/")
+
+	return res, true
+}
+
+func newExcludePatterns(lines []string) excludePatterns {
+	res := make(excludePatterns, 0, len(lines))
+
+	for _, line := range lines {
+		if pattern, ok := newExcludePattern(line); ok {
+			res = append(res, pattern)
+		}
+	}
+
+	return res
+}
+
+func readExcludePatterns(file string) (excludePatterns, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines []string
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return newExcludePatterns(lines), nil
+}
+
+// Match returns true when the slash-separated path, relative to the root
+// directory, matches the pattern.
+func (e excludePattern) Match(rel string, isDir bool) bool {
+	if e.dirOnly && !isDir {
+		return false
+	}
+
+	name := strings.Split(rel, "/")
+
+	if !e.anchored {
+		name = name[len(name)-1:]
+	}
+
+	return matchSegments(e.segments, name)
+}
+
+// Match returns true when the slash-separated path, relative to the root
+// directory, is excluded; the last matching pattern wins.
+func (e excludePatterns) Match(rel string, isDir bool) bool {
+	var res bool
+
+	for _, pattern := range e {
+		if pattern.Match(rel, isDir) {
+			res = !pattern.negate
+		}
+	}
+
+	return res
+}
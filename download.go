@@ -0,0 +1,218 @@
+package versions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/sumdb/dirhash"
+	"golang.org/x/mod/zip"
+
+	"github.com/MarioCarrion/versions/goproxy"
+)
+
+type (
+	// downloader downloads modules into the module cache, using the same
+	// layout as the go command.
+	downloader struct {
+		client   *goproxy.Client
+		noSumDB  string
+		modCache string
+	}
+)
+
+var (
+	// errMissingGoSum indicates the module is not in go.sum and therefore it
+	// can't be verified.
+	errMissingGoSum = errors.New("missing go.sum entry")
+)
+
+// download downloads the modules not in the module cache, modules not found
+// in the proxies are skipped and nothing is downloaded when the proxies are
+// disabled, because of GOPROXY=off. Modules missing their go.sum entry are
+// not downloaded, they are returned with the error instead.
+func download(ctx context.Context, opts options, sum goSum, mods []module.Version) (map[module.Version]error, error) {
+	d := downloader{
+		client:   opts.download,
+		noSumDB:  opts.noSumDB,
+		modCache: opts.modCache,
+	}
+
+	module.Sort(mods)
+
+	res := make(map[module.Version]error)
+
+	for _, mod := range mods {
+		if err := d.Download(ctx, mod, sum); err != nil {
+			if errors.Is(err, goproxy.ErrDisabled) {
+				return res, nil
+			}
+
+			if errors.Is(err, goproxy.ErrNotFound) {
+				continue
+			}
+
+			if errors.Is(err, errMissingGoSum) {
+				res[mod] = err
+				continue
+			}
+
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// writeFile writes the file atomically.
+func writeFile(file string, data []byte) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(file), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), file)
+}
+
+// Download downloads and extracts the module zip, unless it is already in the
+// module cache. The zip, and go.mod file, are verified using the hashes in
+// go.sum; modules not in go.sum are only allowed when their path matches the
+// noSumDB patterns, like GONOSUMDB and GOPRIVATE, otherwise errMissingGoSum is
+// returned before downloading anything.
+func (d downloader) Download(ctx context.Context, mod module.Version, sum goSum) error {
+	escapedPath, err := module.EscapePath(mod.Path)
+	if err != nil {
+		return err
+	}
+
+	escapedVersion, err := module.EscapeVersion(mod.Version)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Join(d.modCache, escapedPath+"@"+escapedVersion)
+	if _, err := os.Stat(dir); err == nil {
+		return nil
+	}
+
+	key := fmt.Sprintf("%s %s", mod.Path, mod.Version)
+	if _, ok := sum[key]; !ok && !module.MatchPrefixPatterns(d.noSumDB, mod.Path) {
+		return fmt.Errorf("%s: %w", key, errMissingGoSum)
+	}
+
+	data, err := d.client.Zip(ctx, mod.Path, mod.Version)
+	if err != nil {
+		return err
+	}
+
+	download := filepath.Join(d.modCache, "cache", "download", escapedPath, "@v")
+	if err := os.MkdirAll(download, 0755); err != nil {
+		return err
+	}
+
+	zipFile := filepath.Join(download, escapedVersion+".zip")
+
+	if err := d.writeZip(zipFile, data, key, sum); err != nil {
+		return err
+	}
+
+	if err := d.writeMod(ctx, mod, filepath.Join(download, escapedVersion+".mod"), sum); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempDir(filepath.Dir(dir), ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	if err := zip.Unzip(tmp, mod, zipFile); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp, dir); err != nil {
+		if _, errStat := os.Stat(dir); errStat == nil { // extracted concurrently
+			return nil
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// writeMod writes the go.mod file of the module, verifying it when go.sum
+// includes its hash; modules without go.mod file in the proxy are skipped.
+func (d downloader) writeMod(ctx context.Context, mod module.Version, file string, sum goSum) error {
+	data, err := d.client.Mod(ctx, mod.Path, mod.Version)
+	if err != nil {
+		if errors.Is(err, goproxy.ErrNotFound) {
+			return nil
+		}
+
+		return err
+	}
+
+	hash, err := hashGoMod(data)
+	if err != nil {
+		return err
+	}
+
+	if err := sum.Verify(fmt.Sprintf("%s %s/go.mod", mod.Path, mod.Version), hash); err != nil {
+		return err
+	}
+
+	return writeFile(file, data)
+}
+
+// writeZip verifies the zip before writing it, as well as its hash, into the
+// file.
+func (d downloader) writeZip(file string, data []byte, key string, sum goSum) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(file), ".tmp-*.zip")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	hash, err := dirhash.HashZip(tmp.Name(), dirhash.Hash1)
+	if err != nil {
+		return err
+	}
+
+	if err := sum.Verify(key, hash); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp.Name(), file); err != nil {
+		return err
+	}
+
+	return writeFile(file+"hash", []byte(hash))
+}
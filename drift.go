@@ -0,0 +1,79 @@
+package versions
+
+import (
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+type (
+	// Drift classifies how far apart two versions of a Package are.
+	Drift string
+)
+
+const (
+	// DriftMajor indicates the major versions are different, or that the
+	// versions are not valid semantic versions and can't be compared.
+	DriftMajor Drift = "major"
+
+	// DriftMinor indicates the minor versions are different.
+	DriftMinor Drift = "minor"
+
+	// DriftNone indicates the versions are the same.
+	DriftNone Drift = ""
+
+	// DriftPatch indicates the patch versions, or the pre-release and build
+	// suffixes, are different.
+	DriftPatch Drift = "patch"
+
+	// DriftPseudo indicates the major versions are the same but at least one
+	// of them is a pseudo-version, so the minor and patch numbers don't
+	// reflect the changes between them.
+	DriftPseudo Drift = "pseudo"
+
+	// DriftReplaced indicates the versions are the same but the replacements
+	// are different.
+	DriftReplaced Drift = "replaced"
+)
+
+// VersionDrift returns the Drift between both versions, the order of the
+// arguments doesn't matter.
+func VersionDrift(a, b string) Drift {
+	if a == b {
+		return DriftNone
+	}
+
+	if !semver.IsValid(a) || !semver.IsValid(b) {
+		return DriftMajor
+	}
+
+	switch {
+	case semver.Compare(a, b) == 0 && semver.Build(a) == semver.Build(b):
+		return DriftNone
+	case semver.Major(a) != semver.Major(b):
+		return DriftMajor
+	case module.IsPseudoVersion(a) || module.IsPseudoVersion(b):
+		return DriftPseudo
+	case semver.MajorMinor(a) != semver.MajorMinor(b):
+		return DriftMinor
+	}
+
+	return DriftPatch
+}
+
+// Severity returns a number indicating how severe the drift is, from 0 (none)
+// to 4 (major); pseudo-versions are more severe than minor drifts because
+// their compatibility can't be inferred from the version.
+func (d Drift) Severity() int {
+	switch d {
+	case DriftPatch:
+		return 1
+	case DriftMinor:
+		return 2
+	case DriftPseudo:
+		return 3
+	case DriftMajor:
+		return 4
+	}
+
+	return 0
+}
@@ -0,0 +1,10 @@
+package versions
+
+// UnregisterRenderer removes the Renderer registered using the format name, so
+// tests registering renderers can be run more than once.
+func UnregisterRenderer(name string) {
+	renderersMu.Lock()
+	defer renderersMu.Unlock()
+
+	delete(renderers, name)
+}
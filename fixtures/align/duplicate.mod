@@ -0,0 +1,10 @@
+module fixture.com/align/duplicate
+
+go 1.15
+
+require (
+	github.com/MarioCarrion/nit v1.23.1 // indirect
+	github.com/google/go-cmp v0.5.0
+)
+
+require github.com/MarioCarrion/nit v1.24.0
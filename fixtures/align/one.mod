@@ -0,0 +1,12 @@
+// Module one.
+module fixture.com/align/one
+
+go 1.15
+
+require (
+	github.com/MarioCarrion/nit v1.23.1 // pinned for a reason
+	github.com/MarioCarrion/swagger-lint v1.0.0 // indirect
+	github.com/google/go-cmp v0.5.0
+)
+
+replace github.com/google/go-cmp => ../go-cmp
@@ -0,0 +1,9 @@
+module fixture.com/align/two
+
+go 1.15
+
+require (
+	github.com/MarioCarrion/nit v1.24.0
+	github.com/MarioCarrion/swagger-lint v1.2.0
+	github.com/google/go-cmp v0.6.0
+)
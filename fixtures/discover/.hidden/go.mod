@@ -0,0 +1,3 @@
+module fixture.com/hidden
+
+go 1.15
@@ -0,0 +1,3 @@
+module fixture.com/b
+
+go 1.15
@@ -0,0 +1,3 @@
+module fixture.com/d
+
+go 1.15
@@ -0,0 +1,3 @@
+module fixture.com/root
+
+go 1.15
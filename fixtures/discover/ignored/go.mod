@@ -0,0 +1,3 @@
+module fixture.com/ignored
+
+go 1.15
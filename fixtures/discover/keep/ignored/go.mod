@@ -0,0 +1,3 @@
+module fixture.com/keep_ignored
+
+go 1.15
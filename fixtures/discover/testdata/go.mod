@@ -0,0 +1,3 @@
+module fixture.com/testdata
+
+go 1.15
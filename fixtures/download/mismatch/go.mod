@@ -0,0 +1,5 @@
+module fixture.com/download/mismatch
+
+go 1.15
+
+require example.com/licensed v1.0.0
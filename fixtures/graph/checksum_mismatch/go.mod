@@ -0,0 +1,5 @@
+module fixture.com/graph/checksum_mismatch
+
+go 1.15
+
+require example.com/a v1.0.0
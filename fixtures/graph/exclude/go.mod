@@ -0,0 +1,10 @@
+module fixture.com/graph/exclude
+
+go 1.15
+
+require (
+	example.com/a v1.0.0
+	example.com/b v1.1.0
+)
+
+exclude example.com/c v1.1.0
@@ -0,0 +1,10 @@
+module fixture.com/graph
+
+go 1.15
+
+require (
+	example.com/a v1.0.0
+	example.com/b v1.1.0
+)
+
+replace example.com/b v1.1.0 => ./local/b
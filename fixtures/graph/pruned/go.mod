@@ -0,0 +1,9 @@
+module fixture.com/graph/pruned
+
+go 1.17
+
+require (
+	example.com/p v1.0.0
+	example.com/s v1.0.0
+	example.com/u v1.0.0
+)
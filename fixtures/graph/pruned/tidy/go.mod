@@ -0,0 +1,8 @@
+module fixture.com/graph/pruned/tidy
+
+go 1.17
+
+require (
+	example.com/s v1.2.0
+	example.com/u v1.0.0
+)
@@ -0,0 +1,10 @@
+module fixture.com/graph/vulnerable/downgraded
+
+go 1.17
+
+require (
+	example.com/downgrader v1.0.0
+	github.com/example/vulnerable v1.2.0
+)
+
+exclude github.com/example/vulnerable v1.2.0
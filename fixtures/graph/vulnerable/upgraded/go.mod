@@ -0,0 +1,8 @@
+module fixture.com/graph/vulnerable/upgraded
+
+go 1.17
+
+require (
+	example.com/upgrader v1.0.0
+	github.com/example/vulnerable v1.1.5
+)
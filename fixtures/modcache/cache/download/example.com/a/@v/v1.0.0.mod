@@ -0,0 +1,5 @@
+module example.com/a
+
+go 1.15
+
+require example.com/c v1.0.0
@@ -0,0 +1,7 @@
+module example.com/b
+
+go 1.15
+
+require (
+	example.com/c v1.1.0
+)
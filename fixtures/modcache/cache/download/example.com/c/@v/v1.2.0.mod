@@ -0,0 +1,5 @@
+module example.com/c
+
+go 1.15
+
+require example.com/e v1.0.0
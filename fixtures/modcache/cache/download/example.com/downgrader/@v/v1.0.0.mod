@@ -0,0 +1,5 @@
+module example.com/downgrader
+
+go 1.17
+
+require github.com/example/vulnerable v1.1.5
@@ -0,0 +1,9 @@
+module example.com/p
+
+go 1.17
+
+require (
+	example.com/q v1.0.0
+	example.com/s v1.2.0
+	fixture.com/graph/pruned v0.1.0
+)
@@ -0,0 +1,8 @@
+module example.com/q
+
+go 1.17
+
+require (
+	example.com/r v1.1.0
+	example.com/s v1.5.0
+)
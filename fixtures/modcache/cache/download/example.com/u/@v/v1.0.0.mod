@@ -0,0 +1,5 @@
+module example.com/u
+
+go 1.15
+
+require example.com/w v1.0.0
@@ -0,0 +1,5 @@
+module example.com/w
+
+go 1.17
+
+require example.com/x v1.0.0
@@ -0,0 +1,14 @@
+module fixture.com/new_module_vulnerable
+
+go 1.15
+
+require (
+	github.com/example/local v1.0.0
+	github.com/example/patched v1.0.0
+	github.com/example/vulnerable v1.1.5
+)
+
+replace (
+	github.com/example/local => ../local
+	github.com/example/patched => github.com/example/replacement v2.0.0
+)
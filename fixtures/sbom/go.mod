@@ -0,0 +1,10 @@
+module fixture.com/sbom
+
+go 1.17
+
+require example.com/a v1.0.0
+
+require (
+	example.com/c v1.2.0 // indirect
+	example.com/d v1.0.0 // indirect
+)
@@ -0,0 +1,10 @@
+module fixture.com/workspace/one
+
+go 1.21
+
+require (
+	github.com/MarioCarrion/nit v1.23.1
+	github.com/MarioCarrion/swagger-lint v1.0.0 // indirect
+)
+
+replace github.com/MarioCarrion/nit => replaced/MarioCarrion/nit v9.0.0
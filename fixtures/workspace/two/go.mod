@@ -0,0 +1,5 @@
+module fixture.com/workspace/two
+
+go 1.20
+
+require github.com/MarioCarrion/nit v1.23.3
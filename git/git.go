@@ -0,0 +1,87 @@
+// Package git runs the git commands needed to commit and push changes made to
+// local repositories.
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+type (
+	// Repository represents a local git repository.
+	Repository struct {
+		root string
+	}
+)
+
+// Open returns the repository containing dir.
+func Open(dir string) (Repository, error) {
+	root, err := run(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return Repository{}, err
+	}
+
+	return Repository{root: root}, nil
+}
+
+func run(dir string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// Checkout switches to the branch.
+func (r Repository) Checkout(branch string) error {
+	_, err := run(r.root, "checkout", branch)
+	return err
+}
+
+// Commit commits the files using the message.
+func (r Repository) Commit(message string, files ...string) error {
+	if _, err := run(r.root, append([]string{"add", "--"}, files...)...); err != nil {
+		return err
+	}
+
+	_, err := run(r.root, "commit", "-m", message)
+
+	return err
+}
+
+// CreateBranch creates the branch, starting at the current commit, and
+// switches to it.
+func (r Repository) CreateBranch(branch string) error {
+	_, err := run(r.root, "checkout", "-b", branch)
+	return err
+}
+
+// CurrentBranch returns the name of the current branch.
+func (r Repository) CurrentBranch() (string, error) {
+	return run(r.root, "rev-parse", "--abbrev-ref", "HEAD")
+}
+
+// Push pushes the branch to the remote.
+func (r Repository) Push(remote, branch string) error {
+	_, err := run(r.root, "push", "--set-upstream", remote, branch)
+	return err
+}
+
+// RemoteURL returns the URL of the remote.
+func (r Repository) RemoteURL(remote string) (string, error) {
+	return run(r.root, "remote", "get-url", remote)
+}
+
+// Root returns the top-level directory of the repository.
+func (r Repository) Root() string {
+	return r.root
+}
@@ -0,0 +1,86 @@
+package git_test
+
+import (
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/MarioCarrion/versions/git"
+)
+
+func Test_Repository(t *testing.T) {
+	t.Parallel()
+
+	remote := t.TempDir()
+	dir := t.TempDir()
+
+	execGit(t, remote, "init", "--bare")
+	execGit(t, dir, "init")
+	execGit(t, dir, "checkout", "-b", "main")
+	execGit(t, dir, "config", "user.email", "versions@example.com")
+	execGit(t, dir, "config", "user.name", "versions")
+	execGit(t, dir, "remote", "add", "origin", remote)
+
+	file := filepath.Join(dir, "go.mod")
+
+	if err := ioutil.WriteFile(file, []byte("module example.com/one\n"), 0644); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	repo, err := git.Open(dir)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if err := repo.Commit("initial", file); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if url, err := repo.RemoteURL("origin"); err != nil || url != remote {
+		t.Fatalf("expected %s, got %s (%v)", remote, url, err)
+	}
+
+	if err := repo.CreateBranch("feature"); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if branch, err := repo.CurrentBranch(); err != nil || branch != "feature" {
+		t.Fatalf("expected feature, got %s (%v)", branch, err)
+	}
+
+	if err := repo.Checkout("main"); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if err := repo.Push("origin", "feature"); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if actual := execGit(t, remote, "rev-parse", "feature"); actual != execGit(t, dir, "rev-parse", "main") {
+		t.Fatalf("expected pushed branch to match, got %s", actual)
+	}
+
+	if err := repo.Checkout("unknown"); err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+
+	if _, err := git.Open(t.TempDir()); err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+}
+
+func execGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %s: %s", args, err, out)
+	}
+
+	return strings.TrimSpace(string(out))
+}
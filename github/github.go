@@ -0,0 +1,165 @@
+// Package github implements a client for the GitHub REST API, supporting the
+// endpoints needed to open pull requests.
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/MarioCarrion/versions/align"
+)
+
+type (
+	// Client calls the GitHub REST API.
+	Client struct {
+		baseURL    string
+		token      string
+		httpClient *http.Client
+	}
+
+	// Option is configuration option for the Client.
+	Option func(*Client)
+
+	// PullRequest represents a GitHub pull request, Number and URL are only
+	// set by the API.
+	PullRequest struct {
+		Title  string `json:"title"`
+		Body   string `json:"body"`
+		Head   string `json:"head"`
+		Base   string `json:"base"`
+		Number int    `json:"number,omitempty"`
+		URL    string `json:"html_url,omitempty"`
+	}
+)
+
+const (
+	// DefaultBaseURL is the URL of the GitHub REST API.
+	DefaultBaseURL = "https://api.github.com"
+)
+
+// NewClient returns a Client authenticating using the token.
+func NewClient(token string, opts ...Option) *Client {
+	c := Client{
+		baseURL:    DefaultBaseURL,
+		token:      token,
+		httpClient: http.DefaultClient,
+	}
+
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	return &c
+}
+
+// ParseRemote returns the owner and repository of the git remote URL,
+// supporting the HTTPS, SSH and SCP-like formats.
+func ParseRemote(remote string) (string, string, error) {
+	var path string
+
+	if u, err := url.Parse(remote); err == nil && u.Scheme != "" {
+		path = u.Path
+	} else if i := strings.Index(remote, ":"); i >= 0 { // git@github.com:owner/repo.git
+		path = remote[i+1:]
+	}
+
+	parts := strings.Split(strings.Trim(strings.TrimSuffix(path, ".git"), "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid GitHub remote %q", remote)
+	}
+
+	return parts[0], parts[1], nil
+}
+
+// WithBaseURL allows specifying the URL of the REST API, for example when
+// using GitHub Enterprise.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithHTTPClient allows specifying the HTTP client used to call the API.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = client
+	}
+}
+
+// CreatePullRequest opens the pull request in the repository.
+func (c *Client) CreatePullRequest(ctx context.Context, owner, repo string, pr PullRequest) (PullRequest, error) {
+	data, err := json.Marshal(pr)
+	if err != nil {
+		return PullRequest{}, err
+	}
+
+	endpoint := fmt.Sprintf("%s/repos/%s/%s/pulls", c.baseURL, url.PathEscape(owner), url.PathEscape(repo))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return PullRequest{}, err
+	}
+
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return PullRequest{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return PullRequest{}, err
+	}
+
+	if resp.StatusCode != http.StatusCreated {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+
+		_ = json.Unmarshal(body, &apiErr)
+
+		return PullRequest{}, fmt.Errorf("creating pull request: unexpected status %s: %s", resp.Status, apiErr.Message)
+	}
+
+	var res PullRequest
+
+	if err := json.Unmarshal(body, &res); err != nil {
+		return PullRequest{}, fmt.Errorf("invalid pull request response: %w", err)
+	}
+
+	return res, nil
+}
+
+// Request opens the pull request in the repository identified by the remote
+// URL, returning its URL; it implements align.Requester.
+func (c *Client) Request(ctx context.Context, remote string, req align.Request) (string, error) {
+	owner, repo, err := ParseRemote(remote)
+	if err != nil {
+		return "", err
+	}
+
+	pr, err := c.CreatePullRequest(ctx, owner, repo, PullRequest{
+		Title: req.Title,
+		Body:  req.Body,
+		Head:  req.Head,
+		Base:  req.Base,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return pr.URL, nil
+}
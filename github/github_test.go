@@ -0,0 +1,167 @@
+package github_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/MarioCarrion/versions/align"
+	"github.com/MarioCarrion/versions/github"
+)
+
+func Test_Client_Request(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		remote   string
+		status   int
+		response string
+		expected string
+		err      bool
+	}{
+		{
+			"OK",
+			"git@github.com:MarioCarrion/versions.git",
+			http.StatusCreated,
+			`{"number":1,"html_url":"https://github.com/MarioCarrion/versions/pull/1"}`,
+			"https://github.com/MarioCarrion/versions/pull/1",
+			false,
+		},
+		{
+			"ERR: unexpected status",
+			"https://github.com/MarioCarrion/versions",
+			http.StatusUnprocessableEntity,
+			`{"message":"Validation Failed"}`,
+			"",
+			true,
+		},
+		{
+			"ERR: invalid response",
+			"https://github.com/MarioCarrion/versions",
+			http.StatusCreated,
+			`{`,
+			"",
+			true,
+		},
+		{
+			"ERR: invalid remote",
+			"https://github.com/MarioCarrion",
+			http.StatusCreated,
+			`{}`,
+			"",
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.URL.Path != "/repos/MarioCarrion/versions/pulls" {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				}
+
+				if auth := r.Header.Get("Authorization"); auth != "Bearer secret" {
+					t.Errorf("unexpected authorization %q", auth)
+				}
+
+				var pr github.PullRequest
+
+				if err := json.NewDecoder(r.Body).Decode(&pr); err != nil {
+					t.Errorf("expected no error, got %s", err)
+				}
+
+				expected := github.PullRequest{Title: "Align", Body: "body", Head: "versions/align", Base: "main"}
+				if !cmp.Equal(pr, expected) {
+					t.Errorf("expected values do not match: %s", cmp.Diff(expected, pr))
+				}
+
+				w.WriteHeader(test.status)
+				_, _ = w.Write([]byte(test.response))
+			}))
+			t.Cleanup(server.Close)
+
+			client := github.NewClient("secret",
+				github.WithBaseURL(server.URL+"/"),
+				github.WithHTTPClient(server.Client()))
+
+			actual, err := client.Request(context.Background(), test.remote, align.Request{
+				Title: "Align",
+				Body:  "body",
+				Head:  "versions/align",
+				Base:  "main",
+			})
+			if (err != nil) != test.err {
+				t.Fatalf("expected error %t, got %v", test.err, err)
+			}
+
+			if actual != test.expected {
+				t.Fatalf("expected %s, got %s", test.expected, actual)
+			}
+		})
+	}
+}
+
+func Test_ParseRemote(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+		owner string
+		repo  string
+		err   bool
+	}{
+		{
+			"OK: https",
+			"https://github.com/MarioCarrion/versions.git",
+			"MarioCarrion",
+			"versions",
+			false,
+		},
+		{
+			"OK: ssh",
+			"ssh://git@github.com/MarioCarrion/versions",
+			"MarioCarrion",
+			"versions",
+			false,
+		},
+		{
+			"OK: scp-like",
+			"git@github.com:MarioCarrion/versions.git",
+			"MarioCarrion",
+			"versions",
+			false,
+		},
+		{
+			"ERR: local path",
+			"/tmp/versions",
+			"",
+			"",
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			owner, repo, err := github.ParseRemote(test.input)
+			if (err != nil) != test.err {
+				t.Fatalf("expected error %t, got %v", test.err, err)
+			}
+
+			if owner != test.owner || repo != test.repo {
+				t.Fatalf("expected %s/%s, got %s/%s", test.owner, test.repo, owner, repo)
+			}
+		})
+	}
+}
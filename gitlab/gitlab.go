@@ -0,0 +1,236 @@
+// Package gitlab implements a client for the GitLab REST API, supporting the
+// endpoints needed to open merge requests.
+package gitlab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/MarioCarrion/versions/align"
+)
+
+type (
+	// Client calls the GitLab REST API.
+	Client struct {
+		baseURL    string
+		token      string
+		httpClient *http.Client
+		labels     []string
+		assignees  []string
+	}
+
+	// MergeRequest represents a GitLab merge request, IID and URL are only
+	// set by the API.
+	MergeRequest struct {
+		Title        string `json:"title"`
+		Description  string `json:"description"`
+		SourceBranch string `json:"source_branch"`
+		TargetBranch string `json:"target_branch"`
+		Labels       string `json:"labels,omitempty"`
+		AssigneeIDs  []int  `json:"assignee_ids,omitempty"`
+		IID          int    `json:"iid,omitempty"`
+		URL          string `json:"web_url,omitempty"`
+	}
+
+	// Option is configuration option for the Client.
+	Option func(*Client)
+)
+
+const (
+	// DefaultBaseURL is the URL of the GitLab REST API.
+	DefaultBaseURL = "https://gitlab.com/api/v4"
+)
+
+// NewClient returns a Client authenticating using the personal, project or
+// group access token.
+func NewClient(token string, opts ...Option) *Client {
+	c := Client{
+		baseURL:    DefaultBaseURL,
+		token:      token,
+		httpClient: http.DefaultClient,
+	}
+
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	return &c
+}
+
+// ParseRemote returns the project path, including subgroups, of the git
+// remote URL, supporting the HTTPS, SSH and SCP-like formats.
+func ParseRemote(remote string) (string, error) {
+	var path string
+
+	if u, err := url.Parse(remote); err == nil && u.Scheme != "" {
+		path = u.Path
+	} else if i := strings.Index(remote, ":"); i >= 0 { // git@gitlab.com:group/project.git
+		path = remote[i+1:]
+	}
+
+	path = strings.Trim(strings.TrimSuffix(path, ".git"), "/")
+
+	parts := strings.Split(path, "/")
+	if len(parts) < 2 {
+		return "", fmt.Errorf("invalid GitLab remote %q", remote)
+	}
+
+	for _, part := range parts {
+		if part == "" {
+			return "", fmt.Errorf("invalid GitLab remote %q", remote)
+		}
+	}
+
+	return path, nil
+}
+
+// WithAssignees allows specifying the usernames of the users assigned to the
+// merge requests.
+func WithAssignees(usernames ...string) Option {
+	return func(c *Client) {
+		c.assignees = usernames
+	}
+}
+
+// WithBaseURL allows specifying the URL of the REST API, for example when
+// using a self-managed GitLab instance.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithHTTPClient allows specifying the HTTP client used to call the API.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = client
+	}
+}
+
+// WithLabels allows specifying the labels added to the merge requests.
+func WithLabels(labels ...string) Option {
+	return func(c *Client) {
+		c.labels = labels
+	}
+}
+
+// CreateMergeRequest opens the merge request in the project.
+func (c *Client) CreateMergeRequest(ctx context.Context, project string, mr MergeRequest) (MergeRequest, error) {
+	data, err := json.Marshal(mr)
+	if err != nil {
+		return MergeRequest{}, err
+	}
+
+	var res MergeRequest
+
+	endpoint := fmt.Sprintf("/projects/%s/merge_requests", url.PathEscape(project))
+
+	if err := c.do(ctx, http.MethodPost, endpoint, bytes.NewReader(data), http.StatusCreated, &res); err != nil {
+		return MergeRequest{}, fmt.Errorf("creating merge request: %w", err)
+	}
+
+	return res, nil
+}
+
+// Request opens the merge request in the project identified by the remote
+// URL, using the configured labels and assignees, returning its URL; it
+// implements align.Requester.
+func (c *Client) Request(ctx context.Context, remote string, req align.Request) (string, error) {
+	project, err := ParseRemote(remote)
+	if err != nil {
+		return "", err
+	}
+
+	ids := make([]int, 0, len(c.assignees))
+
+	for _, username := range c.assignees {
+		id, err := c.UserID(ctx, username)
+		if err != nil {
+			return "", err
+		}
+
+		ids = append(ids, id)
+	}
+
+	mr, err := c.CreateMergeRequest(ctx, project, MergeRequest{
+		Title:        req.Title,
+		Description:  req.Body,
+		SourceBranch: req.Head,
+		TargetBranch: req.Base,
+		Labels:       strings.Join(c.labels, ","),
+		AssigneeIDs:  ids,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return mr.URL, nil
+}
+
+// UserID returns the ID of the user with the username.
+func (c *Client) UserID(ctx context.Context, username string) (int, error) {
+	var users []struct {
+		ID int `json:"id"`
+	}
+
+	if err := c.do(ctx, http.MethodGet, "/users?username="+url.QueryEscape(username), nil, http.StatusOK, &users); err != nil {
+		return 0, fmt.Errorf("finding user: %w", err)
+	}
+
+	if len(users) == 0 {
+		return 0, fmt.Errorf("user %q not found", username)
+	}
+
+	return users[0].ID, nil
+}
+
+func (c *Client) do(ctx context.Context, method, endpoint string, body io.Reader, status int, res interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+endpoint, body)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if c.token != "" {
+		req.Header.Set("PRIVATE-TOKEN", c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != status {
+		var apiErr struct {
+			Message interface{} `json:"message"`
+		}
+
+		_ = json.Unmarshal(data, &apiErr)
+
+		return fmt.Errorf("unexpected status %s: %v", resp.Status, apiErr.Message)
+	}
+
+	if err := json.Unmarshal(data, res); err != nil {
+		return fmt.Errorf("invalid response: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,201 @@
+package gitlab_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/MarioCarrion/versions/align"
+	"github.com/MarioCarrion/versions/gitlab"
+)
+
+func Test_Client_Request(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		remote   string
+		status   int
+		response string
+		expected string
+		err      bool
+	}{
+		{
+			"OK",
+			"git@gitlab.com:group/subgroup/project.git",
+			http.StatusCreated,
+			`{"iid":1,"web_url":"https://gitlab.com/group/subgroup/project/-/merge_requests/1"}`,
+			"https://gitlab.com/group/subgroup/project/-/merge_requests/1",
+			false,
+		},
+		{
+			"ERR: unexpected status",
+			"https://gitlab.com/group/subgroup/project",
+			http.StatusConflict,
+			`{"message":["Another open merge request already exists for this source branch"]}`,
+			"",
+			true,
+		},
+		{
+			"ERR: invalid response",
+			"https://gitlab.com/group/subgroup/project",
+			http.StatusCreated,
+			`{`,
+			"",
+			true,
+		},
+		{
+			"ERR: unknown assignee",
+			"https://gitlab.com/group/subgroup/project",
+			http.StatusCreated,
+			`{}`,
+			"",
+			true,
+		},
+		{
+			"ERR: invalid remote",
+			"https://gitlab.com/project",
+			http.StatusCreated,
+			`{}`,
+			"",
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("/api/v4/users", func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Query().Get("username") {
+				case "mario":
+					_, _ = w.Write([]byte(`[{"id":10}]`))
+				case "carrion":
+					if test.name == "ERR: unknown assignee" {
+						_, _ = w.Write([]byte(`[]`))
+						return
+					}
+
+					_, _ = w.Write([]byte(`[{"id":20}]`))
+				}
+			})
+			mux.HandleFunc("/api/v4/projects/", func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.URL.EscapedPath() != "/api/v4/projects/group%2Fsubgroup%2Fproject/merge_requests" {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.EscapedPath())
+				}
+
+				if token := r.Header.Get("PRIVATE-TOKEN"); token != "secret" {
+					t.Errorf("unexpected token %q", token)
+				}
+
+				var mr gitlab.MergeRequest
+
+				if err := json.NewDecoder(r.Body).Decode(&mr); err != nil {
+					t.Errorf("expected no error, got %s", err)
+				}
+
+				expected := gitlab.MergeRequest{
+					Title:        "Align",
+					Description:  "body",
+					SourceBranch: "versions/align",
+					TargetBranch: "main",
+					Labels:       "dependencies,versions",
+					AssigneeIDs:  []int{10, 20},
+				}
+				if !cmp.Equal(mr, expected) {
+					t.Errorf("expected values do not match: %s", cmp.Diff(expected, mr))
+				}
+
+				w.WriteHeader(test.status)
+				_, _ = w.Write([]byte(test.response))
+			})
+
+			server := httptest.NewServer(mux)
+			t.Cleanup(server.Close)
+
+			client := gitlab.NewClient("secret",
+				gitlab.WithAssignees("mario", "carrion"),
+				gitlab.WithBaseURL(server.URL+"/api/v4/"),
+				gitlab.WithHTTPClient(server.Client()),
+				gitlab.WithLabels("dependencies", "versions"))
+
+			actual, err := client.Request(context.Background(), test.remote, align.Request{
+				Title: "Align",
+				Body:  "body",
+				Head:  "versions/align",
+				Base:  "main",
+			})
+			if (err != nil) != test.err {
+				t.Fatalf("expected error %t, got %v", test.err, err)
+			}
+
+			if actual != test.expected {
+				t.Fatalf("expected %s, got %s", test.expected, actual)
+			}
+		})
+	}
+}
+
+func Test_ParseRemote(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		err      bool
+	}{
+		{
+			"OK: https",
+			"https://gitlab.com/group/project.git",
+			"group/project",
+			false,
+		},
+		{
+			"OK: ssh",
+			"ssh://git@gitlab.example.com:2222/group/subgroup/project",
+			"group/subgroup/project",
+			false,
+		},
+		{
+			"OK: scp-like",
+			"git@gitlab.com:group/subgroup/project.git",
+			"group/subgroup/project",
+			false,
+		},
+		{
+			"ERR: missing group",
+			"https://gitlab.com/project",
+			"",
+			true,
+		},
+		{
+			"ERR: local path",
+			"/tmp/project",
+			"",
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := gitlab.ParseRemote(test.input)
+			if (err != nil) != test.err {
+				t.Fatalf("expected error %t, got %v", test.err, err)
+			}
+
+			if actual != test.expected {
+				t.Fatalf("expected %s, got %s", test.expected, actual)
+			}
+		})
+	}
+}
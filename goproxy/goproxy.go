@@ -0,0 +1,276 @@
+// Package goproxy implements a client for the GOPROXY protocol, supporting
+// HTTP(S) and file:// proxies as well as the "off" and "direct" values.
+package goproxy
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+type (
+	// Client queries GOPROXY-compatible proxies, in order, using the same
+	// fallback rules as the go command.
+	Client struct {
+		proxies    []proxy
+		noProxy    string
+		httpClient *http.Client
+	}
+
+	// Info represents the metadata of a module version.
+	Info struct {
+		Version string
+		Time    time.Time
+	}
+
+	// Option is configuration option for the Client.
+	Option func(*Client)
+
+	proxy struct {
+		url *url.URL
+		// fallbackOnError indicates the next proxy is used on any error, when
+		// false the next proxy is used only when the module is not found.
+		fallbackOnError bool
+		direct          bool
+		off             bool
+	}
+)
+
+const (
+	// DefaultGOPROXY is the default value used when GOPROXY is empty.
+	DefaultGOPROXY = "https://proxy.golang.org,direct"
+)
+
+var (
+	// ErrDisabled indicates the module lookup is disabled, because of
+	// GOPROXY=off.
+	ErrDisabled = errors.New("module lookup disabled by GOPROXY=off")
+
+	// ErrNotFound indicates the module or version was not found in any proxy,
+	// including the case where the only remaining option is "direct", which is
+	// not supported.
+	ErrNotFound = errors.New("module not found")
+)
+
+// NewClient returns a Client using the proxies defined in the goproxy value,
+// which uses the same format as the GOPROXY environment variable.
+func NewClient(goproxy string, opts ...Option) (*Client, error) {
+	if goproxy == "" {
+		goproxy = DefaultGOPROXY
+	}
+
+	c := Client{
+		httpClient: http.DefaultClient,
+	}
+
+	for goproxy != "" {
+		var (
+			value           string
+			fallbackOnError bool
+		)
+
+		if i := strings.IndexAny(goproxy, ",|"); i >= 0 {
+			value = goproxy[:i]
+			fallbackOnError = goproxy[i] == '|'
+			goproxy = goproxy[i+1:]
+		} else {
+			value = goproxy
+			goproxy = ""
+		}
+
+		value = strings.TrimSpace(value)
+
+		switch value {
+		case "":
+			continue
+		case "direct":
+			c.proxies = append(c.proxies, proxy{direct: true})
+		case "off":
+			c.proxies = append(c.proxies, proxy{off: true})
+		default:
+			u, err := url.Parse(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid proxy %q: %w", value, err)
+			}
+
+			switch u.Scheme {
+			case "http", "https", "file":
+			case "":
+				// The go command assumes https when the scheme is missing.
+				if u, err = url.Parse("https://" + value); err != nil {
+					return nil, fmt.Errorf("invalid proxy %q: %w", value, err)
+				}
+			default:
+				return nil, fmt.Errorf("invalid proxy %q: unsupported scheme %q", value, u.Scheme)
+			}
+
+			c.proxies = append(c.proxies, proxy{url: u, fallbackOnError: fallbackOnError})
+		}
+	}
+
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	return &c, nil
+}
+
+// WithHTTPClient allows specifying the HTTP client used to query the proxies.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = client
+	}
+}
+
+// WithNoProxy allows specifying the comma-separated list of glob patterns of
+// module paths that should not be queried using the proxies, like GONOPROXY
+// and GOPRIVATE.
+func WithNoProxy(patterns string) Option {
+	return func(c *Client) {
+		c.noProxy = patterns
+	}
+}
+
+// Latest returns the latest version of the module, it corresponds to the
+// "$module/@latest" endpoint.
+func (c *Client) Latest(ctx context.Context, path string) (Info, error) {
+	data, err := c.fetch(ctx, path, "@latest")
+	if err != nil {
+		return Info{}, err
+	}
+
+	var info Info
+
+	if err := json.Unmarshal(data, &info); err != nil {
+		return Info{}, fmt.Errorf("invalid latest info for %s: %w", path, err)
+	}
+
+	return info, nil
+}
+
+// List returns the sorted list of known tagged versions of the module, it
+// corresponds to the "$module/@v/list" endpoint.
+func (c *Client) List(ctx context.Context, path string) ([]string, error) {
+	data, err := c.fetch(ctx, path, "@v/list")
+	if err != nil {
+		return nil, err
+	}
+
+	var res []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || !semver.IsValid(fields[0]) {
+			continue
+		}
+
+		res = append(res, fields[0])
+	}
+
+	semver.Sort(res)
+
+	return res, nil
+}
+
+// Mod returns the go.mod file of the module version, it corresponds to the
+// "$module/@v/$version.mod" endpoint.
+func (c *Client) Mod(ctx context.Context, path, version string) ([]byte, error) {
+	escaped, err := module.EscapeVersion(version)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.fetch(ctx, path, "@v/"+escaped+".mod")
+}
+
+// Zip returns the zip file of the module version, it corresponds to the
+// "$module/@v/$version.zip" endpoint.
+func (c *Client) Zip(ctx context.Context, path, version string) ([]byte, error) {
+	escaped, err := module.EscapeVersion(version)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.fetch(ctx, path, "@v/"+escaped+".zip")
+}
+
+func (c *Client) fetch(ctx context.Context, path, endpoint string) ([]byte, error) {
+	escaped, err := module.EscapePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(c.proxies) == 0 || module.MatchPrefixPatterns(c.noProxy, path) {
+		return nil, ErrNotFound
+	}
+
+	var lastErr error
+
+	for _, p := range c.proxies {
+		switch {
+		case p.off:
+			return nil, ErrDisabled
+		case p.direct:
+			return nil, ErrNotFound
+		}
+
+		data, err := p.fetch(ctx, c.httpClient, escaped+"/"+endpoint)
+		if err == nil {
+			return data, nil
+		}
+
+		lastErr = err
+
+		if !p.fallbackOnError && !errors.Is(err, ErrNotFound) {
+			return nil, err
+		}
+	}
+
+	return nil, lastErr
+}
+
+func (p proxy) fetch(ctx context.Context, client *http.Client, endpoint string) ([]byte, error) {
+	if p.url.Scheme == "file" {
+		data, err := ioutil.ReadFile(filepath.Join(filepath.FromSlash(p.url.Path), filepath.FromSlash(endpoint)))
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%s: %w", endpoint, ErrNotFound)
+		}
+
+		return data, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(p.url.String(), "/")+"/"+endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusGone:
+		return nil, fmt.Errorf("%s: %w", endpoint, ErrNotFound)
+	default:
+		return nil, fmt.Errorf("%s: unexpected status %s", endpoint, resp.Status)
+	}
+
+	return ioutil.ReadAll(resp.Body)
+}
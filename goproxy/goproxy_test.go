@@ -0,0 +1,358 @@
+package goproxy_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/MarioCarrion/versions/goproxy"
+)
+
+func Test_Client(t *testing.T) {
+	t.Parallel()
+
+	dir, err := filepath.Abs(filepath.Join("..", "fixtures", "goproxy"))
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	fileProxy := "file://" + filepath.ToSlash(dir)
+
+	fileServer := httptest.NewServer(http.FileServer(http.Dir(dir)))
+	t.Cleanup(fileServer.Close)
+
+	failServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(failServer.Close)
+
+	type (
+		input struct {
+			goproxy string
+			opts    []goproxy.Option
+			path    string
+		}
+
+		expected struct {
+			list   []string
+			latest goproxy.Info
+			err    error
+		}
+	)
+
+	tests := []struct {
+		name     string
+		input    input
+		expected expected
+	}{
+		{
+			"OK: file",
+			input{
+				goproxy: fileProxy,
+				path:    "github.com/MarioCarrion/nit",
+			},
+			expected{
+				list: []string{"v1.23.1", "v1.23.3", "v1.24.0"},
+				latest: goproxy.Info{
+					Version: "v1.24.0",
+					Time:    time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+		{
+			"OK: http",
+			input{
+				goproxy: fileServer.URL,
+				path:    "github.com/MarioCarrion/nit",
+			},
+			expected{
+				list: []string{"v1.23.1", "v1.23.3", "v1.24.0"},
+				latest: goproxy.Info{
+					Version: "v1.24.0",
+					Time:    time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+		{
+			"OK: fallback when not found",
+			input{
+				goproxy: fileProxy + "/does_not_exist," + fileServer.URL,
+				path:    "github.com/MarioCarrion/nit",
+			},
+			expected{
+				list: []string{"v1.23.1", "v1.23.3", "v1.24.0"},
+				latest: goproxy.Info{
+					Version: "v1.24.0",
+					Time:    time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+		{
+			"OK: fallback on any error",
+			input{
+				goproxy: failServer.URL + "|" + fileProxy,
+				path:    "github.com/MarioCarrion/nit",
+			},
+			expected{
+				list: []string{"v1.23.1", "v1.23.3", "v1.24.0"},
+				latest: goproxy.Info{
+					Version: "v1.24.0",
+					Time:    time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+		{
+			"Error: no fallback on errors",
+			input{
+				goproxy: failServer.URL + "," + fileProxy,
+				path:    "github.com/MarioCarrion/nit",
+			},
+			expected{
+				err: errors.New("unexpected status"),
+			},
+		},
+		{
+			"Error: not found",
+			input{
+				goproxy: fileProxy + "," + fileServer.URL,
+				path:    "example.com/does_not_exist",
+			},
+			expected{
+				err: goproxy.ErrNotFound,
+			},
+		},
+		{
+			"Error: direct",
+			input{
+				goproxy: "direct," + fileProxy,
+				path:    "github.com/MarioCarrion/nit",
+			},
+			expected{
+				err: goproxy.ErrNotFound,
+			},
+		},
+		{
+			"Error: no proxy",
+			input{
+				goproxy: fileProxy,
+				opts:    []goproxy.Option{goproxy.WithNoProxy("example.com,github.com/MarioCarrion")},
+				path:    "github.com/MarioCarrion/nit",
+			},
+			expected{
+				err: goproxy.ErrNotFound,
+			},
+		},
+		{
+			"Error: off",
+			input{
+				goproxy: fileProxy + "/does_not_exist,off",
+				path:    "github.com/MarioCarrion/nit",
+			},
+			expected{
+				err: goproxy.ErrDisabled,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, err := goproxy.NewClient(test.input.goproxy, test.input.opts...)
+			if err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+
+			list, err := client.List(context.Background(), test.input.path)
+			if !matchError(err, test.expected.err) {
+				t.Fatalf("expected error %v, got %v", test.expected.err, err)
+			}
+
+			if !cmp.Equal(list, test.expected.list) {
+				t.Fatalf("expected values do not match: %s", cmp.Diff(list, test.expected.list))
+			}
+
+			latest, err := client.Latest(context.Background(), test.input.path)
+			if !matchError(err, test.expected.err) {
+				t.Fatalf("expected error %v, got %v", test.expected.err, err)
+			}
+
+			if !cmp.Equal(latest, test.expected.latest) {
+				t.Fatalf("expected values do not match: %s", cmp.Diff(latest, test.expected.latest))
+			}
+		})
+	}
+}
+
+func Test_Client_ModZip(t *testing.T) {
+	t.Parallel()
+
+	dir, err := filepath.Abs(filepath.Join("..", "fixtures", "goproxy"))
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	fileServer := httptest.NewServer(http.FileServer(http.Dir(dir)))
+	t.Cleanup(fileServer.Close)
+
+	mod, err := ioutil.ReadFile(filepath.Join(dir, "example.com", "licensed", "@v", "v1.0.0.mod"))
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	zip, err := ioutil.ReadFile(filepath.Join(dir, "example.com", "licensed", "@v", "v1.0.0.zip"))
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	type (
+		input struct {
+			goproxy string
+			version string
+		}
+
+		expected struct {
+			mod []byte
+			zip []byte
+			err error
+		}
+	)
+
+	tests := []struct {
+		name     string
+		input    input
+		expected expected
+	}{
+		{
+			"OK: file",
+			input{
+				goproxy: "file://" + filepath.ToSlash(dir),
+				version: "v1.0.0",
+			},
+			expected{
+				mod: mod,
+				zip: zip,
+			},
+		},
+		{
+			"OK: http",
+			input{
+				goproxy: fileServer.URL,
+				version: "v1.0.0",
+			},
+			expected{
+				mod: mod,
+				zip: zip,
+			},
+		},
+		{
+			"Error: not found",
+			input{
+				goproxy: fileServer.URL,
+				version: "v2.0.0",
+			},
+			expected{
+				err: goproxy.ErrNotFound,
+			},
+		},
+		{
+			"Error: invalid version",
+			input{
+				goproxy: fileServer.URL,
+				version: "v1.0.0/../..",
+			},
+			expected{
+				err: errors.New("invalid"),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, err := goproxy.NewClient(test.input.goproxy)
+			if err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+
+			mod, err := client.Mod(context.Background(), "example.com/licensed", test.input.version)
+			if !matchError(err, test.expected.err) {
+				t.Fatalf("expected error %v, got %v", test.expected.err, err)
+			}
+
+			if !cmp.Equal(mod, test.expected.mod) {
+				t.Fatalf("expected values do not match: %s", cmp.Diff(mod, test.expected.mod))
+			}
+
+			zip, err := client.Zip(context.Background(), "example.com/licensed", test.input.version)
+			if !matchError(err, test.expected.err) {
+				t.Fatalf("expected error %v, got %v", test.expected.err, err)
+			}
+
+			if !cmp.Equal(zip, test.expected.zip) {
+				t.Fatalf("expected zip does not match, got %d bytes", len(zip))
+			}
+		})
+	}
+}
+
+func Test_NewClient(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+		err   bool
+	}{
+		{
+			"OK: default",
+			"",
+			false,
+		},
+		{
+			"OK: multiple",
+			"https://proxy.example.com|file:///tmp/proxy,proxy.example.com,direct,off",
+			false,
+		},
+		{
+			"Invalid: scheme",
+			"ftp://proxy.example.com",
+			true,
+		},
+		{
+			"Invalid: url",
+			"https://proxy.example.com/%zz",
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := goproxy.NewClient(test.input); (err != nil) != test.err {
+				t.Fatalf("expected error %t, got %s", test.err, err)
+			}
+		})
+	}
+}
+
+func matchError(err, expected error) bool {
+	if expected == nil || err == nil {
+		return err == expected
+	}
+
+	return errors.Is(err, expected) || strings.Contains(err.Error(), expected.Error())
+}
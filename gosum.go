@@ -0,0 +1,93 @@
+package versions
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"golang.org/x/mod/sumdb/dirhash"
+)
+
+type (
+	// goSum contains the hashes defined in a go.sum file indexed by
+	// "<path> <version>" for module zips and "<path> <version>/go.mod" for
+	// go.mod files.
+	goSum map[string][]string
+)
+
+func hashGoMod(data []byte) (string, error) {
+	return dirhash.Hash1([]string{"go.mod"}, func(string) (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(data)), nil
+	})
+}
+
+// readGoSum returns the hashes defined in the go.sum file, a missing file is
+// not considered an error.
+func readGoSum(file string) (goSum, error) {
+	res := make(goSum)
+
+	f, err := os.Open(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return res, nil
+		}
+
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			continue
+		}
+
+		key := fmt.Sprintf("%s %s", fields[0], fields[1])
+		res[key] = append(res[key], fields[2])
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Merge adds the hashes defined in other.
+func (g goSum) Merge(other goSum) {
+	for key, hashes := range other {
+	loop:
+		for _, hash := range hashes {
+			for _, h := range g[key] {
+				if h == hash {
+					continue loop
+				}
+			}
+
+			g[key] = append(g[key], hash)
+		}
+	}
+}
+
+// Verify returns an error when go.sum defines hashes for the key and none of
+// them matches the hash; keys not defined in go.sum are not considered an
+// error.
+func (g goSum) Verify(key, hash string) error {
+	hashes, ok := g[key]
+	if !ok {
+		return nil
+	}
+
+	for _, h := range hashes {
+		if h == hash {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%s: checksum mismatch, downloaded %s, go.sum %s", key, hash, strings.Join(hashes, " "))
+}
@@ -0,0 +1,372 @@
+package versions
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+type (
+	// ModuleGraph represents the module requirement graph of a Module, built
+	// using the go.mod files of its dependencies found in the module cache and
+	// pruned like the go command does for modules declaring Go 1.17 or greater.
+	ModuleGraph struct {
+		root         module.Version
+		requirements map[module.Version][]module.Version
+		missing      map[module.Version]bool
+		excludes     map[module.Version]bool
+	}
+
+	graphLoader struct {
+		modCache string
+		dir      string
+		goSum    goSum
+		replaces map[module.Version]module.Version
+	}
+)
+
+// isPruned returns true when the modfile declares Go 1.17 or greater, and
+// therefore its module graph is pruned.
+func isPruned(f *modfile.File) bool {
+	if f.Go == nil {
+		return false
+	}
+
+	var major, minor int
+
+	if _, err := fmt.Sscanf(f.Go.Version, "%d.%d", &major, &minor); err != nil {
+		return false
+	}
+
+	return major > 1 || (major == 1 && minor >= 17)
+}
+
+func newGraphLoader(modCache string, f *modfile.File) (graphLoader, error) {
+	dir := filepath.Dir(f.Syntax.Name)
+
+	sum, err := readGoSum(filepath.Join(dir, "go.sum"))
+	if err != nil {
+		return graphLoader{}, err
+	}
+
+	loader := graphLoader{
+		modCache: modCache,
+		dir:      dir,
+		goSum:    sum,
+		replaces: make(map[module.Version]module.Version),
+	}
+
+	for _, replace := range f.Replace {
+		loader.replaces[replace.Old] = replace.New
+	}
+
+	return loader, nil
+}
+
+// newModuleGraph builds the requirement graph of the module defined in the
+// modfile, using the go.mod files found in the module cache, replace and
+// exclude directives in the modfile are honored; requirements on excluded
+// versions are ignored.
+//
+// The graph is pruned when the modfile declares Go 1.17 or greater: only the
+// direct requirements of dependencies also declaring Go 1.17 or greater are
+// included, the go.mod files of those requirements are not loaded; the
+// requirements of dependencies declaring older versions are loaded
+// transitively.
+func newModuleGraph(modCache string, f *modfile.File) (ModuleGraph, error) {
+	loader, err := newGraphLoader(modCache, f)
+	if err != nil {
+		return ModuleGraph{}, err
+	}
+
+	graph := ModuleGraph{
+		root:         module.Version{Path: f.Module.Mod.Path},
+		requirements: make(map[module.Version][]module.Version),
+		missing:      make(map[module.Version]bool),
+		excludes:     make(map[module.Version]bool),
+	}
+
+	for _, exclude := range f.Exclude {
+		graph.excludes[exclude.Mod] = true
+	}
+
+	graph.requirements[graph.root] = graph.filter(requirements(f))
+
+	type pending struct {
+		mod    module.Version
+		pruned bool
+	}
+
+	var queue []pending
+
+	for _, req := range graph.requirements[graph.root] {
+		queue = append(queue, pending{mod: req, pruned: isPruned(f)})
+	}
+
+	prunedDeps := make(map[module.Version]bool)
+	expanded := make(map[module.Version]bool)
+
+	for len(queue) > 0 {
+		next := queue[0]
+		queue = queue[1:]
+
+		mod := next.mod
+
+		if graph.missing[mod] || (!next.pruned && expanded[mod]) {
+			continue
+		}
+
+		reqs, ok := graph.requirements[mod]
+		if ok && next.pruned {
+			continue
+		}
+
+		if !ok {
+			dep, err := loader.Load(mod)
+			if err != nil {
+				return ModuleGraph{}, err
+			}
+
+			if dep == nil {
+				graph.missing[mod] = true
+				continue
+			}
+
+			reqs = graph.filter(requirements(dep))
+			graph.requirements[mod] = reqs
+			prunedDeps[mod] = isPruned(dep)
+		}
+
+		if next.pruned && prunedDeps[mod] {
+			continue
+		}
+
+		if !next.pruned {
+			expanded[mod] = true
+		}
+
+		for _, req := range reqs {
+			queue = append(queue, pending{mod: req})
+		}
+	}
+
+	return graph, nil
+}
+
+func requirements(f *modfile.File) []module.Version {
+	res := make([]module.Version, len(f.Require))
+
+	for i, require := range f.Require {
+		res[i] = require.Mod
+	}
+
+	module.Sort(res)
+
+	return res
+}
+
+// BuildList returns the modules selected by Minimal Version Selection, that
+// is the highest version of each module path required anywhere in the graph,
+// sorted by path and excluding any version of the root module.
+func (g ModuleGraph) BuildList() []module.Version {
+	selected := make(map[string]string)
+
+	for _, mod := range g.Modules() {
+		g.selectVersion(selected, mod)
+	}
+
+	res := make([]module.Version, 0, len(selected))
+
+	for path, version := range selected {
+		res = append(res, module.Version{Path: path, Version: version})
+	}
+
+	module.Sort(res)
+
+	return res
+}
+
+// IsMissing returns true when the go.mod file of the module was not found in
+// the module cache, and therefore its requirements are unknown.
+func (g ModuleGraph) IsMissing(mod module.Version) bool {
+	return g.missing[mod]
+}
+
+// Modules returns all the modules required in the graph, excluding any
+// version of the root module, sorted by path and version.
+func (g ModuleGraph) Modules() []module.Version {
+	seen := make(map[module.Version]bool)
+	res := make([]module.Version, 0, len(g.requirements))
+
+	for _, reqs := range g.requirements {
+		for _, req := range reqs {
+			if req.Path != g.root.Path && !seen[req] {
+				seen[req] = true
+				res = append(res, req)
+			}
+		}
+	}
+
+	module.Sort(res)
+
+	return res
+}
+
+// Requirements returns the modules directly required by the module.
+func (g ModuleGraph) Requirements(mod module.Version) []module.Version {
+	return append([]module.Version{}, g.requirements[mod]...)
+}
+
+// Root returns the root module of the graph.
+func (g ModuleGraph) Root() module.Version {
+	return g.root
+}
+
+// Selected returns the version of the package selected by Minimal Version
+// Selection, or an empty string if the package is not in the graph.
+func (g ModuleGraph) Selected(name PackageName) string {
+	for _, mod := range g.BuildList() {
+		if mod.Path == string(name) {
+			return mod.Version
+		}
+	}
+
+	return ""
+}
+
+// Why returns the shortest chain of requirements, starting with the root
+// module, that explains why any version of the package is in the graph; nil
+// is returned when the package is not in the graph.
+func (g ModuleGraph) Why(name PackageName) []module.Version {
+	parents := map[module.Version]module.Version{}
+	visited := map[module.Version]bool{g.root: true}
+	queue := []module.Version{g.root}
+
+	for len(queue) > 0 {
+		mod := queue[0]
+		queue = queue[1:]
+
+		if mod.Path == string(name) && mod != g.root {
+			var res []module.Version
+
+			for ; mod != g.root; mod = parents[mod] {
+				res = append(res, mod)
+			}
+
+			res = append(res, g.root)
+
+			for i, j := 0, len(res)-1; i < j; i, j = i+1, j-1 {
+				res[i], res[j] = res[j], res[i]
+			}
+
+			return res
+		}
+
+		for _, req := range g.requirements[mod] {
+			if !visited[req] {
+				visited[req] = true
+				parents[req] = mod
+				queue = append(queue, req)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (g ModuleGraph) filter(mods []module.Version) []module.Version {
+	res := make([]module.Version, 0, len(mods))
+
+	for _, mod := range mods {
+		if !g.excludes[mod] {
+			res = append(res, mod)
+		}
+	}
+
+	return res
+}
+
+func (g ModuleGraph) selectVersion(selected map[string]string, mod module.Version) {
+	if mod.Path == g.root.Path {
+		return
+	}
+
+	if current, ok := selected[mod.Path]; !ok || semver.Compare(mod.Version, current) > 0 {
+		selected[mod.Path] = mod.Version
+	}
+}
+
+// Load returns the parsed go.mod file of the module, or nil if it is not
+// available in the module cache.
+func (l graphLoader) Load(mod module.Version) (*modfile.File, error) {
+	target, ok := l.replaces[mod]
+	if !ok {
+		target, ok = l.replaces[module.Version{Path: mod.Path}]
+	}
+
+	if ok && target.Version == "" { // local directory
+		dir := target.Path
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(l.dir, dir)
+		}
+
+		return l.parse(filepath.Join(dir, "go.mod"), "")
+	}
+
+	if !ok {
+		target = mod
+	}
+
+	escapedPath, err := module.EscapePath(target.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	escapedVersion, err := module.EscapeVersion(target.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("%s %s/go.mod", target.Path, target.Version)
+
+	for _, file := range []string{
+		filepath.Join(l.modCache, "cache", "download", escapedPath, "@v", escapedVersion+".mod"),
+		filepath.Join(l.modCache, escapedPath+"@"+escapedVersion, "go.mod"),
+	} {
+		f, err := l.parse(file, key)
+		if err != nil || f != nil {
+			return f, err
+		}
+	}
+
+	return nil, nil
+}
+
+func (l graphLoader) parse(file, goSumKey string) (*modfile.File, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	if goSumKey != "" {
+		hash, err := hashGoMod(data)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := l.goSum.Verify(goSumKey, hash); err != nil {
+			return nil, err
+		}
+	}
+
+	return modfile.ParseLax(file, data, nil)
+}
@@ -0,0 +1,217 @@
+// Package graphviz allows versions to be rendered as a Graphviz DOT graph.
+package graphviz
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/MarioCarrion/versions"
+)
+
+type (
+	// Graphviz renders versions as a Graphviz DOT directed graph, where modules
+	// and packages are nodes and the edges indicate the required versions.
+	Graphviz struct {
+		versions versions.Versions
+	}
+
+	edge struct {
+		module versions.ModuleName
+		pkg    versions.Package
+	}
+)
+
+// NewGraphviz instantiates a new template for rendering in Graphviz DOT.
+func NewGraphviz(v versions.Versions) Graphviz {
+	return Graphviz{
+		versions: v,
+	}
+}
+
+// driftColor returns the color indicating the severity of the drift, or an
+// empty string when there is no drift.
+func driftColor(drift versions.Drift) string {
+	switch drift {
+	case versions.DriftPatch:
+		return "gold"
+	case versions.DriftMinor:
+		return "orange"
+	case versions.DriftPseudo:
+		return "purple"
+	case versions.DriftMajor:
+		return "red"
+	case versions.DriftReplaced:
+		return "blue"
+	}
+
+	return ""
+}
+
+func init() {
+	versions.RegisterRenderer("graphviz", func(v versions.Versions, _ versions.RendererOptions) versions.Renderer {
+		return NewGraphviz(v)
+	})
+}
+
+func quote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+	return fmt.Sprintf(`"%s"`, r.Replace(s))
+}
+
+// updates returns the newest versions available, or an empty string when
+// there are none.
+func updates(u versions.Updates) string {
+	var values []string
+
+	for _, update := range []struct {
+		name    string
+		version string
+	}{
+		{"patch", u.Patch},
+		{"minor", u.Minor},
+		{"major", u.Major},
+	} {
+		if update.version != "" {
+			values = append(values, fmt.Sprintf("%s %s", update.name, update.version))
+		}
+	}
+
+	if len(values) == 0 {
+		return ""
+	}
+
+	return "updates: " + strings.Join(values, ", ")
+}
+
+// Render writes versions in Graphviz DOT format to w.
+//
+// Modules are rendered as boxes, labeled with their efferent (Ce) and afferent
+// (Ca) coupling and instability (I), and packages as ellipses, labeled with
+// their afferent coupling. Packages not using the same version across all
+// modules, and the edges pointing to versions lower than the highest one, are
+// colored by the severity of their drift: gold for patch, orange for minor,
+// purple for pseudo-versions and red for major; packages, and all their edges,
+// are blue when only the replacements differ. Edges pointing to replaced
+// packages are dashed and edges pointing to indirect packages are dotted,
+// their labels include the version selected by Minimal Version Selection, when
+// different, and the newest versions available, when known.
+func (g Graphviz) Render(w io.Writer) error {
+	names := make([]string, 0, len(g.versions.Modules))
+	for name := range g.versions.Modules {
+		names = append(names, string(name))
+	}
+
+	sort.Strings(names)
+
+	pkgs := g.versions.Packages.Names()
+	sort.Slice(pkgs, func(i, j int) bool { return pkgs[i] < pkgs[j] })
+
+	var b strings.Builder
+
+	b.WriteString("digraph versions {\n")
+	b.WriteString("\trankdir=LR;\n")
+	b.WriteString("\tnode [shape=ellipse];\n")
+
+	for _, name := range names {
+		mod := g.versions.Modules[versions.ModuleName(name)]
+		coupling := g.versions.ModuleCoupling(mod.Name)
+		fmt.Fprintf(&b, "\t%s [shape=box, label=%s];\n",
+			quote(name),
+			quote(fmt.Sprintf("%s\ngo %s\nCe %d, Ca %d, I %.2f",
+				mod.Name, mod.GoVersion, coupling.Efferent, coupling.Afferent, coupling.Instability())))
+	}
+
+	var edges []edge
+
+	for _, name := range pkgs {
+		if _, ok := g.versions.Modules[versions.ModuleName(name)]; !ok {
+			label := fmt.Sprintf("%s\nCa %d", name, g.versions.PackageCoupling(name).Afferent)
+
+			attrs := []string{fmt.Sprintf("label=%s", quote(label))}
+			if !g.versions.Packages.IsSame(name) {
+				color := driftColor(g.versions.Packages.Drift(name))
+				attrs = append(attrs, "color="+color, "fontcolor="+color)
+			}
+
+			fmt.Fprintf(&b, "\t%s [%s];\n", quote(string(name)), strings.Join(attrs, ", "))
+		}
+
+		for mod, pkg := range g.versions.Packages.Values(name) {
+			edges = append(edges, edge{module: mod, pkg: pkg})
+		}
+	}
+
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].module == edges[j].module {
+			return edges[i].pkg.Name < edges[j].pkg.Name
+		}
+
+		return edges[i].module < edges[j].module
+	})
+
+	for _, e := range edges {
+		var color string
+
+		if drift := g.versions.Packages.Drift(e.pkg.Name); drift == versions.DriftReplaced {
+			color = driftColor(drift)
+		} else if drift != versions.DriftNone {
+			color = driftColor(versions.VersionDrift(e.pkg.EffectiveVersion(), g.versions.Packages.Highest(e.pkg.Name)))
+		}
+
+		fmt.Fprintf(&b, "\t%s -> %s [%s];\n",
+			quote(string(e.module)),
+			quote(string(e.pkg.Name)),
+			strings.Join(e.attributes(color), ", "))
+	}
+
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// String returns versions in Graphviz DOT format.
+func (g Graphviz) String() string {
+	var b strings.Builder
+
+	if err := g.Render(&b); err != nil {
+		return ""
+	}
+
+	return b.String()
+}
+
+func (e edge) attributes(color string) []string {
+	label := e.pkg.Version
+
+	if e.pkg.SelectedVersion != "" && e.pkg.SelectedVersion != e.pkg.Version {
+		label += ", selected " + e.pkg.SelectedVersion
+	}
+
+	if e.pkg.ReplacedPath != "" {
+		label = strings.TrimSpace(fmt.Sprintf("%s => %s %s", label, e.pkg.ReplacedPath, e.pkg.ReplacedVersion))
+	}
+
+	if u := updates(e.pkg.Updates); u != "" {
+		label += "\n" + u
+	}
+
+	res := []string{fmt.Sprintf("label=%s", quote(label))}
+
+	switch {
+	case e.pkg.ReplacedPath != "":
+		res = append(res, "style=dashed")
+	case e.pkg.IsIndirect:
+		res = append(res, "style=dotted")
+	}
+
+	if color != "" {
+		res = append(res, "color="+color, "fontcolor="+color)
+	}
+
+	return res
+}
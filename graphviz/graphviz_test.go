@@ -0,0 +1,219 @@
+package graphviz
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/MarioCarrion/versions"
+)
+
+func Test_Graphviz_String(t *testing.T) {
+	t.Parallel()
+
+	type inputPkg struct {
+		name versions.ModuleName
+		pkg  versions.Package
+	}
+
+	tests := []struct {
+		name     string
+		input    []inputPkg
+		expected string
+	}{
+		{
+			"OK: Empty",
+			nil,
+			"digraph versions {\n" +
+				"\trankdir=LR;\n" +
+				"\tnode [shape=ellipse];\n" +
+				"}\n",
+		},
+		{
+			"OK",
+			[]inputPkg{
+				{
+					"Module2",
+					versions.Package{
+						Name:    "pkg1",
+						Version: "v1",
+						Updates: versions.Updates{
+							Minor: "v1.1.0",
+							Major: "v2.0.0",
+						},
+					},
+				},
+				{
+					"Module2",
+					versions.Package{
+						Name:            "pkg2",
+						Version:         "v2",
+						ReplacedPath:    "replaced/pkg2",
+						ReplacedVersion: "v3",
+					},
+				},
+				{
+					"Module1",
+					versions.Package{
+						Name:       "pkg2",
+						Version:    "v1",
+						IsIndirect: true,
+					},
+				},
+				{
+					"Module1",
+					versions.Package{
+						Name:    "Module2",
+						Version: "v0.1.0",
+					},
+				},
+			},
+			"digraph versions {\n" +
+				"\trankdir=LR;\n" +
+				"\tnode [shape=ellipse];\n" +
+				"\t\"Module1\" [shape=box, label=\"Module1\\ngo 1.15\\nCe 2, Ca 0, I 1.00\"];\n" +
+				"\t\"Module2\" [shape=box, label=\"Module2\\ngo 1.15\\nCe 2, Ca 1, I 0.67\"];\n" +
+				"\t\"pkg1\" [label=\"pkg1\\nCa 1\"];\n" +
+				"\t\"pkg2\" [label=\"pkg2\\nCa 2\", color=red, fontcolor=red];\n" +
+				"\t\"Module1\" -> \"Module2\" [label=\"v0.1.0\"];\n" +
+				"\t\"Module1\" -> \"pkg2\" [label=\"v1\", style=dotted, color=red, fontcolor=red];\n" +
+				"\t\"Module2\" -> \"pkg1\" [label=\"v1\\nupdates: minor v1.1.0, major v2.0.0\"];\n" +
+				"\t\"Module2\" -> \"pkg2\" [label=\"v2 => replaced/pkg2 v3\", style=dashed];\n" +
+				"}\n",
+		},
+		{
+			"OK: minor drift",
+			[]inputPkg{
+				{
+					"Module1",
+					versions.Package{
+						Name:    "pkg1",
+						Version: "v1.2.0",
+					},
+				},
+				{
+					"Module2",
+					versions.Package{
+						Name:    "pkg1",
+						Version: "v1.3.0",
+					},
+				},
+			},
+			"digraph versions {\n" +
+				"\trankdir=LR;\n" +
+				"\tnode [shape=ellipse];\n" +
+				"\t\"Module1\" [shape=box, label=\"Module1\\ngo 1.15\\nCe 1, Ca 0, I 1.00\"];\n" +
+				"\t\"Module2\" [shape=box, label=\"Module2\\ngo 1.15\\nCe 1, Ca 0, I 1.00\"];\n" +
+				"\t\"pkg1\" [label=\"pkg1\\nCa 2\", color=orange, fontcolor=orange];\n" +
+				"\t\"Module1\" -> \"pkg1\" [label=\"v1.2.0\", color=orange, fontcolor=orange];\n" +
+				"\t\"Module2\" -> \"pkg1\" [label=\"v1.3.0\"];\n" +
+				"}\n",
+		},
+		{
+			"OK: selected versions drift",
+			[]inputPkg{
+				{
+					"Module1",
+					versions.Package{
+						Name:            "pkg1",
+						Version:         "v1.2.0",
+						SelectedVersion: "v1.2.0",
+					},
+				},
+				{
+					"Module2",
+					versions.Package{
+						Name:            "pkg1",
+						Version:         "v1.2.0",
+						SelectedVersion: "v1.3.0",
+					},
+				},
+			},
+			"digraph versions {\n" +
+				"\trankdir=LR;\n" +
+				"\tnode [shape=ellipse];\n" +
+				"\t\"Module1\" [shape=box, label=\"Module1\\ngo 1.15\\nCe 1, Ca 0, I 1.00\"];\n" +
+				"\t\"Module2\" [shape=box, label=\"Module2\\ngo 1.15\\nCe 1, Ca 0, I 1.00\"];\n" +
+				"\t\"pkg1\" [label=\"pkg1\\nCa 2\", color=orange, fontcolor=orange];\n" +
+				"\t\"Module1\" -> \"pkg1\" [label=\"v1.2.0\", color=orange, fontcolor=orange];\n" +
+				"\t\"Module2\" -> \"pkg1\" [label=\"v1.2.0, selected v1.3.0\"];\n" +
+				"}\n",
+		},
+		{
+			"OK: replaced",
+			[]inputPkg{
+				{
+					"Module1",
+					versions.Package{
+						Name:    "pkg1",
+						Version: "v1.2.0",
+					},
+				},
+				{
+					"Module2",
+					versions.Package{
+						Name:         "pkg1",
+						Version:      "v1.2.0",
+						ReplacedPath: "../pkg1",
+					},
+				},
+			},
+			"digraph versions {\n" +
+				"\trankdir=LR;\n" +
+				"\tnode [shape=ellipse];\n" +
+				"\t\"Module1\" [shape=box, label=\"Module1\\ngo 1.15\\nCe 1, Ca 0, I 1.00\"];\n" +
+				"\t\"Module2\" [shape=box, label=\"Module2\\ngo 1.15\\nCe 1, Ca 0, I 1.00\"];\n" +
+				"\t\"pkg1\" [label=\"pkg1\\nCa 2\", color=blue, fontcolor=blue];\n" +
+				"\t\"Module1\" -> \"pkg1\" [label=\"v1.2.0\", color=blue, fontcolor=blue];\n" +
+				"\t\"Module2\" -> \"pkg1\" [label=\"v1.2.0 => ../pkg1\", style=dashed, color=blue, fontcolor=blue];\n" +
+				"}\n",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			v := versions.Versions{
+				Modules: make(map[versions.ModuleName]versions.Module),
+			}
+
+			for _, p := range test.input {
+				mod, ok := v.Modules[p.name]
+				if !ok {
+					mod = versions.Module{
+						ModuleGoVersion: versions.ModuleGoVersion{
+							Name:      p.name,
+							GoVersion: "1.15",
+						},
+						DependencyRequirements: make(map[versions.PackageName]versions.Package),
+					}
+				}
+
+				mod.DependencyRequirements[p.pkg.Name] = p.pkg
+				v.Modules[p.name] = mod
+
+				v.GoVersions.Set(p.name, mod.GoVersion)
+				v.Packages.Set(p.name, p.pkg)
+			}
+
+			g := NewGraphviz(v)
+
+			if got := g.String(); got != test.expected {
+				t.Fatalf("expected values do not match: %s", cmp.Diff(got, test.expected))
+			}
+
+			var b strings.Builder
+
+			if err := g.Render(&b); err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+
+			if got := b.String(); got != test.expected {
+				t.Fatalf("expected values do not match: %s", cmp.Diff(got, test.expected))
+			}
+		})
+	}
+}
@@ -0,0 +1,438 @@
+// Package html allows versions to be rendered as a self-contained HTML page,
+// including the styles and scripts used for sorting and filtering, so it can
+// be opened offline.
+package html
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/MarioCarrion/versions"
+)
+
+type (
+	// HTML renders versions as a static HTML page: modules are columns and
+	// packages are rows.
+	HTML struct {
+		versions     versions.Versions
+		title        string
+		showCoupling bool
+	}
+
+	// Option is configuration option for this renderer.
+	Option func(*HTML)
+
+	//-
+
+	cell struct {
+		Present         bool
+		Version         string
+		Selected        string
+		Replacement     string
+		License         string
+		Category        string
+		Vulnerabilities []string
+		Class           string
+	}
+
+	column struct {
+		Name      string
+		GoVersion string
+		Coupling  string
+	}
+
+	document struct {
+		Title      string
+		CSS        template.CSS
+		JS         template.JS
+		GoSame     bool
+		Modules    []column
+		Rows       []row
+		Categories []string
+		Drifts     []string
+	}
+
+	row struct {
+		Name       string
+		Drift      string
+		Categories string
+		Coupling   string
+		Cells      []cell
+	}
+)
+
+const (
+	// categoryUnknown is used by packages without a detected license.
+	categoryUnknown = "unknown"
+
+	// driftSame is used by packages using the same version in all modules.
+	driftSame = "same"
+)
+
+const (
+	css = `
+body { margin: 0; font: 14px/1.4 -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; color: #24292f; }
+h1 { margin: 0; font-size: 18px; }
+header { display: flex; flex-wrap: wrap; gap: 8px 16px; align-items: center; padding: 12px 16px; border-bottom: 1px solid #d0d7de; }
+header input, header select { font: inherit; padding: 2px 6px; }
+main { overflow: auto; max-height: calc(100vh - 60px); }
+table { border-collapse: separate; border-spacing: 0; }
+th, td { padding: 4px 8px; border-right: 1px solid #d0d7de; border-bottom: 1px solid #d0d7de; text-align: left; vertical-align: top; white-space: nowrap; background: #fff; }
+thead th { position: sticky; top: 0; z-index: 1; background: #f6f8fa; cursor: pointer; user-select: none; }
+th:first-child, td:first-child { position: sticky; left: 0; z-index: 1; }
+thead th:first-child { z-index: 2; }
+th[aria-sort=ascending]::after { content: " \25B2"; }
+th[aria-sort=descending]::after { content: " \25BC"; }
+small { display: block; color: #57606a; }
+.go-differs { color: #cf222e; font-weight: bold; }
+td.highest { background: #f6f8fa; }
+td.drift-patch { background: #fff8c5; }
+td.drift-minor { background: #ffe2c2; }
+td.drift-pseudo { background: #eddeff; }
+td.drift-major { background: #ffd7d5; }
+td.drift-replaced { background: #ddf4ff; }
+.vulnerability { color: #cf222e; }
+`
+
+	js = `
+(function () {
+  var table = document.getElementById("versions");
+  var headers = Array.prototype.slice.call(table.tHead.rows[0].cells);
+  var rows = Array.prototype.slice.call(table.tBodies[0].rows);
+  var filters = {
+    name: document.getElementById("filter-name"),
+    module: document.getElementById("filter-module"),
+    category: document.getElementById("filter-category"),
+    drift: document.getElementById("filter-drift")
+  };
+
+  function filter() {
+    var name = filters.name.value.toLowerCase();
+    var module = filters.module.value.toLowerCase();
+    var category = filters.category.value;
+    var drift = filters.drift.value;
+
+    rows.forEach(function (row) {
+      row.hidden = row.dataset.name.toLowerCase().indexOf(name) === -1 ||
+        (category !== "" && row.dataset.categories.split(" ").indexOf(category) === -1) ||
+        (drift !== "" && row.dataset.drift !== drift);
+    });
+
+    headers.forEach(function (header, i) {
+      if (i === 0) {
+        return;
+      }
+
+      var hidden = header.dataset.module.toLowerCase().indexOf(module) === -1;
+
+      header.hidden = hidden;
+      rows.forEach(function (row) {
+        row.cells[i].hidden = hidden;
+      });
+    });
+  }
+
+  function sort(i) {
+    var ascending = headers[i].getAttribute("aria-sort") !== "ascending";
+
+    headers.forEach(function (header) {
+      header.removeAttribute("aria-sort");
+    });
+    headers[i].setAttribute("aria-sort", ascending ? "ascending" : "descending");
+
+    rows.sort(function (a, b) {
+      var res = a.cells[i].dataset.sort.localeCompare(b.cells[i].dataset.sort, undefined, {numeric: true});
+
+      return ascending ? res : -res;
+    });
+
+    rows.forEach(function (row) {
+      table.tBodies[0].appendChild(row);
+    });
+  }
+
+  Object.keys(filters).forEach(function (key) {
+    filters[key].addEventListener("input", filter);
+  });
+
+  headers.forEach(function (header, i) {
+    header.addEventListener("click", function () {
+      sort(i);
+    });
+  });
+})();
+`
+
+	page = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>{{.CSS}}</style>
+</head>
+<body>
+<header>
+<h1>{{.Title}}</h1>
+<input id="filter-name" type="search" placeholder="Package" aria-label="Package">
+<input id="filter-module" type="search" placeholder="Module" aria-label="Module">
+<select id="filter-category" aria-label="License category">
+<option value="">All licenses</option>
+{{- range .Categories}}
+<option value="{{.}}">{{.}}</option>
+{{- end}}
+</select>
+<select id="filter-drift" aria-label="Drift">
+<option value="">All drifts</option>
+{{- range .Drifts}}
+<option value="{{.}}">{{.}}</option>
+{{- end}}
+</select>
+</header>
+<main>
+<table id="versions">
+<thead>
+<tr>
+<th data-module="">Package</th>
+{{- range .Modules}}
+<th data-module="{{.Name}}">{{.Name}}<small{{if not $.GoSame}} class="go-differs"{{end}}>go {{.GoVersion}}</small>
+{{- if .Coupling}}<small>{{.Coupling}}</small>{{end -}}
+</th>
+{{- end}}
+</tr>
+</thead>
+<tbody>
+{{- range .Rows}}
+<tr data-name="{{.Name}}" data-drift="{{.Drift}}" data-categories="{{.Categories}}">
+<td data-sort="{{.Name}}">{{.Name}}{{if .Coupling}}<small>{{.Coupling}}</small>{{end}}</td>
+{{- range .Cells}}
+{{- if .Present}}
+<td data-sort="{{.Version}}"{{if .Class}} class="{{.Class}}"{{end}}>{{.Version}}
+{{- if .Selected}}<small>selected {{.Selected}}</small>{{end}}
+{{- if .Replacement}}<small>=&gt; {{.Replacement}}</small>{{end}}
+{{- if .License}}<small>{{.License}}</small>{{end}}
+{{- range .Vulnerabilities}}<small class="vulnerability">&#9888; {{.}}</small>{{end -}}
+</td>
+{{- else}}
+<td data-sort=""></td>
+{{- end}}
+{{- end}}
+</tr>
+{{- end}}
+</tbody>
+</table>
+</main>
+<script>{{.JS}}</script>
+</body>
+</html>
+`
+)
+
+var (
+	tmpl = template.Must(template.New("html").Parse(page))
+)
+
+// NewHTML instantiates a new template for rendering in HTML.
+func NewHTML(v versions.Versions, opts ...Option) HTML {
+	h := HTML{
+		versions: v,
+		title:    "versions",
+	}
+
+	for _, opt := range opts {
+		opt(&h)
+	}
+
+	return h
+}
+
+// WithCoupling allows displaying the coupling metrics: the efferent (Ce) and
+// afferent (Ca) coupling and instability (I) of each module, and the afferent
+// coupling of each package.
+func WithCoupling(opt bool) Option {
+	return func(h *HTML) {
+		h.showCoupling = opt
+	}
+}
+
+// WithTitle allows specifying the title of the page, it defaults to
+// "versions".
+func WithTitle(title string) Option {
+	return func(h *HTML) {
+		h.title = title
+	}
+}
+
+func init() {
+	versions.RegisterRenderer("html", func(v versions.Versions, o versions.RendererOptions) versions.Renderer {
+		return NewHTML(v, WithCoupling(o.Coupling))
+	})
+}
+
+func newCell(pkg versions.Package, drift versions.Drift, highest string) cell {
+	res := cell{
+		Present:     true,
+		Version:     pkg.Version,
+		Replacement: strings.TrimSpace(pkg.ReplacedPath + " " + pkg.ReplacedVersion),
+		Category:    string(pkg.License.Category),
+	}
+
+	if pkg.SelectedVersion != pkg.Version {
+		res.Selected = pkg.SelectedVersion
+	}
+
+	switch pkg.License.Status {
+	case versions.LicenseStatusDetected, "":
+		res.License = strings.TrimSpace(pkg.License.Identifier + " " + string(pkg.License.Category))
+	case versions.LicenseStatusUnrecognized:
+		res.License = string(pkg.License.Status) + " " + pkg.License.Identifier
+	default:
+		res.License = string(pkg.License.Status)
+	}
+
+	if res.Category == "" {
+		res.Category = categoryUnknown
+	}
+
+	for _, vuln := range pkg.Vulnerabilities {
+		res.Vulnerabilities = append(res.Vulnerabilities, strings.TrimSpace(vuln.ID+" "+vuln.Severity))
+	}
+
+	switch drift {
+	case versions.DriftNone:
+	case versions.DriftReplaced:
+		res.Class = "drift-" + string(drift)
+	default:
+		res.Class = "highest"
+
+		if drift := versions.VersionDrift(pkg.EffectiveVersion(), highest); drift != versions.DriftNone {
+			res.Class = "drift-" + string(drift)
+		}
+	}
+
+	return res
+}
+
+// Render writes versions in HTML format to w.
+//
+// Packages not using the same version across all modules are colored by the
+// severity of the drift of each version: yellow for patch, orange for minor,
+// purple for pseudo-versions and red for major, the highest versions are
+// grayed; all versions are blue when only the replacements differ.
+func (h HTML) Render(w io.Writer) error {
+	return tmpl.Execute(w, h.document())
+}
+
+// String returns versions in HTML format.
+func (h HTML) String() string {
+	var b strings.Builder
+
+	if err := h.Render(&b); err != nil {
+		return ""
+	}
+
+	return b.String()
+}
+
+// document returns the values used by the template, modules and packages are
+// sorted alphabetically by their name.
+func (h HTML) document() document {
+	res := document{
+		Title:  h.title,
+		CSS:    template.CSS(css),
+		JS:     template.JS(js),
+		GoSame: h.versions.GoVersions.IsSame(),
+		Drifts: []string{
+			driftSame,
+			string(versions.DriftPatch),
+			string(versions.DriftMinor),
+			string(versions.DriftPseudo),
+			string(versions.DriftMajor),
+			string(versions.DriftReplaced),
+		},
+	}
+
+	names := make([]versions.ModuleName, 0, len(h.versions.Modules))
+	for name := range h.versions.Modules {
+		names = append(names, name)
+	}
+
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+
+	for _, name := range names {
+		col := column{
+			Name:      string(name),
+			GoVersion: string(h.versions.Modules[name].GoVersion),
+		}
+
+		if h.showCoupling {
+			coupling := h.versions.ModuleCoupling(name)
+			col.Coupling = fmt.Sprintf("Ce %d, Ca %d, I %.2f", coupling.Efferent, coupling.Afferent, coupling.Instability())
+		}
+
+		res.Modules = append(res.Modules, col)
+	}
+
+	pkgs := h.versions.Packages.Names()
+	sort.Slice(pkgs, func(i, j int) bool { return pkgs[i] < pkgs[j] })
+
+	categories := make(map[string]bool)
+
+	for _, pkgName := range pkgs {
+		drift := h.versions.Packages.Drift(pkgName)
+		highest := h.versions.Packages.Highest(pkgName)
+
+		r := row{
+			Name:  string(pkgName),
+			Drift: driftSame,
+			Cells: make([]cell, len(names)),
+		}
+
+		if drift != versions.DriftNone {
+			r.Drift = string(drift)
+		}
+
+		if h.showCoupling {
+			r.Coupling = fmt.Sprintf("Ca %d", h.versions.PackageCoupling(pkgName).Afferent)
+		}
+
+		var (
+			rowCategories []string
+			seen          = make(map[string]bool)
+		)
+
+		for i, name := range names {
+			pkg, ok := h.versions.Modules[name].DependencyRequirements[pkgName]
+			if !ok {
+				continue
+			}
+
+			r.Cells[i] = newCell(pkg, drift, highest)
+
+			category := r.Cells[i].Category
+
+			if !categories[category] {
+				categories[category] = true
+				res.Categories = append(res.Categories, category)
+			}
+
+			if !seen[category] {
+				seen[category] = true
+				rowCategories = append(rowCategories, category)
+			}
+		}
+
+		sort.Strings(rowCategories)
+
+		r.Categories = strings.Join(rowCategories, " ")
+
+		res.Rows = append(res.Rows, r)
+	}
+
+	sort.Strings(res.Categories)
+
+	return res
+}
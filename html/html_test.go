@@ -0,0 +1,200 @@
+package html
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/senseyeio/diligent"
+
+	"github.com/MarioCarrion/versions"
+)
+
+func Test_HTML_Render(t *testing.T) {
+	t.Parallel()
+
+	h := NewHTML(newVersions(), WithTitle("Report <1>"))
+
+	var b strings.Builder
+
+	if err := h.Render(&b); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	got := b.String()
+
+	if got != h.String() {
+		t.Fatalf("expected values do not match: %s", cmp.Diff(got, h.String()))
+	}
+
+	for _, expected := range []string{
+		"<title>Report &lt;1&gt;</title>",
+		`<th data-module="Module1">Module1<small class="go-differs">go 1.15</small></th>`,
+		`<tr data-name="pkg1" data-drift="same" data-categories="permissive">`,
+		`<td data-sort="v1.2.0" class="drift-minor">v1.2.0<small>unrecognized Beerware</small><small class="vulnerability">&#9888; GO-2021-0001 HIGH</small></td>`,
+		`<td data-sort="v1.3.0" class="highest">v1.3.0<small>selected v1.3.1</small><small>=&gt; replaced/pkg2 v1.4.0</small><small>not-downloaded</small></td>`,
+		`<option value="permissive">permissive</option>`,
+		"position: sticky;",
+		`document.getElementById("filter-drift")`,
+	} {
+		if !strings.Contains(got, expected) {
+			t.Fatalf("expected %q in rendered HTML", expected)
+		}
+	}
+
+	for _, external := range []string{"<link", "src=", "http://", "https://"} {
+		if strings.Contains(got, external) {
+			t.Fatalf("expected no external resources, found %q", external)
+		}
+	}
+}
+
+func Test_HTML_Render_WithCoupling(t *testing.T) {
+	t.Parallel()
+
+	got := NewHTML(newVersions(), WithCoupling(true)).String()
+
+	for _, expected := range []string{
+		`<th data-module="Module1">Module1<small class="go-differs">go 1.15</small><small>Ce 2, Ca 0, I 1.00</small></th>`,
+		`<td data-sort="pkg2">pkg2<small>Ca 2</small></td>`,
+	} {
+		if !strings.Contains(got, expected) {
+			t.Fatalf("expected %q in rendered HTML", expected)
+		}
+	}
+}
+
+func Test_HTML_document(t *testing.T) {
+	t.Parallel()
+
+	expected := document{
+		Title:  "versions",
+		GoSame: false,
+		Modules: []column{
+			{Name: "Module1", GoVersion: "1.15"},
+			{Name: "Module2", GoVersion: "1.14"},
+		},
+		Rows: []row{
+			{
+				Name:       "pkg1",
+				Drift:      "same",
+				Categories: "permissive",
+				Cells: []cell{
+					{},
+					{Present: true, Version: "v1.0.0", License: "MIT permissive", Category: "permissive"},
+				},
+			},
+			{
+				Name:       "pkg2",
+				Drift:      "minor",
+				Categories: "unknown",
+				Cells: []cell{
+					{
+						Present:         true,
+						Version:         "v1.2.0",
+						License:         "unrecognized Beerware",
+						Category:        "unknown",
+						Vulnerabilities: []string{"GO-2021-0001 HIGH"},
+						Class:           "drift-minor",
+					},
+					{
+						Present:     true,
+						Version:     "v1.3.0",
+						Selected:    "v1.3.1",
+						Replacement: "replaced/pkg2 v1.4.0",
+						License:     "not-downloaded",
+						Category:    "unknown",
+						Class:       "highest",
+					},
+				},
+			},
+			{
+				Name:       "pkg3",
+				Drift:      "replaced",
+				Categories: "unknown",
+				Cells: []cell{
+					{Present: true, Version: "v1.0.0", Category: "unknown", Class: "drift-replaced"},
+					{Present: true, Version: "v1.0.0", Replacement: "../pkg3", Category: "unknown", Class: "drift-replaced"},
+				},
+			},
+		},
+		Categories: []string{"permissive", "unknown"},
+		Drifts:     []string{"same", "patch", "minor", "pseudo", "major", "replaced"},
+	}
+
+	got := NewHTML(newVersions()).document()
+
+	if !cmp.Equal(got, expected, cmp.AllowUnexported(document{}), cmpopts.IgnoreFields(document{}, "CSS", "JS")) {
+		t.Fatalf("expected values do not match: %s", cmp.Diff(got, expected, cmpopts.IgnoreFields(document{}, "CSS", "JS")))
+	}
+}
+
+func newVersions() versions.Versions {
+	modules := map[versions.ModuleName][]versions.Package{
+		"Module1": {
+			{
+				Name:    "pkg2",
+				Version: "v1.2.0",
+				License: versions.License{
+					Identifier: "Beerware",
+					Status:     versions.LicenseStatusUnrecognized,
+				},
+				Vulnerabilities: []versions.Vulnerability{
+					{ID: "GO-2021-0001", Severity: "HIGH", Fixed: "v1.3.0"},
+				},
+			},
+			{
+				Name:    "pkg3",
+				Version: "v1.0.0",
+			},
+		},
+		"Module2": {
+			{
+				Name:    "pkg1",
+				Version: "v1.0.0",
+				License: versions.License{
+					Identifier: "MIT",
+					Category:   diligent.Permissive,
+					Status:     versions.LicenseStatusDetected,
+				},
+			},
+			{
+				Name:            "pkg2",
+				Version:         "v1.3.0",
+				SelectedVersion: "v1.3.1",
+				ReplacedPath:    "replaced/pkg2",
+				ReplacedVersion: "v1.4.0",
+				License: versions.License{
+					Status: versions.LicenseStatusNotDownloaded,
+				},
+			},
+			{
+				Name:         "pkg3",
+				Version:      "v1.0.0",
+				ReplacedPath: "../pkg3",
+			},
+		},
+	}
+
+	res := versions.Versions{
+		Modules: make(map[versions.ModuleName]versions.Module),
+	}
+
+	for name, goVersion := range map[versions.ModuleName]versions.GoVersion{"Module1": "1.15", "Module2": "1.14"} {
+		mod := versions.Module{
+			ModuleGoVersion:        versions.ModuleGoVersion{Name: name, GoVersion: goVersion},
+			DependencyRequirements: make(map[versions.PackageName]versions.Package),
+		}
+
+		for _, pkg := range modules[name] {
+			mod.DependencyRequirements[pkg.Name] = pkg
+			res.Packages.Set(name, pkg)
+		}
+
+		res.Modules[name] = mod
+		res.GoVersions.Set(name, goVersion)
+	}
+
+	return res
+}
@@ -0,0 +1,104 @@
+// Package sbomtest provides the versions shared by the tests of the packages
+// generating software bill of materials.
+package sbomtest
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/senseyeio/diligent"
+
+	"github.com/MarioCarrion/versions"
+)
+
+const (
+	// Module is the name of the module described by NewVersions.
+	Module versions.ModuleName = "fixture.com/module"
+
+	// ModuleGraph is the name of the module described by NewModuleGraph.
+	ModuleGraph versions.ModuleName = "fixture.com/sbom"
+)
+
+// NewModuleGraph returns the versions, including the module graph, of the
+// "fixtures/sbom/go.mod" file using "fixtures/modcache" as module cache; it
+// sets GOMODCACHE so tests calling it must not be run in parallel.
+func NewModuleGraph(t *testing.T) versions.Versions {
+	t.Helper()
+
+	_, file, _, ok := runtime.Caller(0)
+	if !ok {
+		t.Fatalf("expected caller information")
+	}
+
+	fixtures := filepath.Join(filepath.Dir(file), "..", "..", "fixtures")
+
+	old := os.Getenv("GOMODCACHE")
+	os.Setenv("GOMODCACHE", filepath.Join(fixtures, "modcache"))
+	defer os.Setenv("GOMODCACHE", old)
+
+	v, err := versions.New([]string{filepath.Join(fixtures, "sbom", "go.mod")}, versions.WithModuleGraph(), versions.WithoutLicenses())
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	return v
+}
+
+// NewVersions returns the versions of Module requiring: a package with a
+// detected license and an incompatible version, an indirect one with a
+// selected version, one replaced by a local directory and one replaced by a
+// module using an unrecognized license.
+func NewVersions() versions.Versions {
+	mod := versions.Module{
+		ModuleGoVersion: versions.ModuleGoVersion{Name: Module, GoVersion: "1.15"},
+		DependencyRequirements: map[versions.PackageName]versions.Package{
+			"github.com/Example/incompatible": {
+				Name:    "github.com/Example/incompatible",
+				Version: "v2.0.0+incompatible",
+				License: versions.License{
+					Identifier: "MIT",
+					Category:   diligent.Permissive,
+					Status:     versions.LicenseStatusDetected,
+				},
+			},
+			"github.com/example/indirect": {
+				Name:            "github.com/example/indirect",
+				Version:         "v1.0.0",
+				SelectedVersion: "v1.1.0",
+				IsIndirect:      true,
+				License: versions.License{
+					Status: versions.LicenseStatusNotFound,
+				},
+			},
+			"github.com/example/local": {
+				Name:         "github.com/example/local",
+				Version:      "v1.0.0",
+				ReplacedPath: "../local",
+			},
+			"github.com/example/replaced": {
+				Name:            "github.com/example/replaced",
+				Version:         "v1.0.0",
+				ReplacedPath:    "github.com/fork/replaced",
+				ReplacedVersion: "v1.5.0",
+				License: versions.License{
+					Identifier: "Beerware",
+					Status:     versions.LicenseStatusUnrecognized,
+				},
+			},
+		},
+	}
+
+	res := versions.Versions{
+		Modules: map[versions.ModuleName]versions.Module{mod.Name: mod},
+	}
+
+	res.GoVersions.Set(mod.Name, mod.GoVersion)
+
+	for _, pkg := range mod.DependencyRequirements {
+		res.Packages.Set(mod.Name, pkg)
+	}
+
+	return res
+}
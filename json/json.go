@@ -0,0 +1,397 @@
+// Package json allows versions to be rendered as JSON, using a stable and
+// versioned schema.
+package json
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/senseyeio/diligent"
+
+	"github.com/MarioCarrion/versions"
+)
+
+type (
+	// Coupling represents the coupling metrics of a module or package.
+	Coupling struct {
+		Afferent    int     `json:"afferent"`
+		Efferent    int     `json:"efferent"`
+		Instability float64 `json:"instability"`
+	}
+
+	// Document represents the versioned schema rendered as JSON.
+	Document struct {
+		SchemaVersion int          `json:"schemaVersion"`
+		GoVersions    GoVersions   `json:"goVersions"`
+		Modules       []Module     `json:"modules"`
+		Packages      []PackageSet `json:"packages"`
+		Workspaces    []Workspace  `json:"workspaces"`
+	}
+
+	// GoVersions represents the alignment of Go versions used by all modules.
+	GoVersions struct {
+		Same bool `json:"same"`
+	}
+
+	// License represents the license used by a package, Status is one of the
+	// versions.LicenseStatus values.
+	License struct {
+		Identifier string  `json:"identifier"`
+		Name       string  `json:"name"`
+		ShortName  string  `json:"shortName"`
+		Type       string  `json:"type"`
+		Category   string  `json:"category"`
+		Status     string  `json:"status"`
+		Error      string  `json:"error"`
+		Confidence float32 `json:"confidence"`
+	}
+
+	// Module represents a parsed go.mod file.
+	Module struct {
+		Name      string    `json:"name"`
+		GoVersion string    `json:"goVersion"`
+		Packages  []Package `json:"packages"`
+		Coupling  Coupling  `json:"coupling"`
+	}
+
+	// Package represents a package required by a module.
+	Package struct {
+		Name            string          `json:"name"`
+		Version         string          `json:"version"`
+		SelectedVersion string          `json:"selectedVersion"`
+		IsIndirect      bool            `json:"indirect"`
+		ReplacedPath    string          `json:"replacedPath"`
+		ReplacedVersion string          `json:"replacedVersion"`
+		License         License         `json:"license"`
+		Updates         Updates         `json:"updates"`
+		Vulnerabilities []Vulnerability `json:"vulnerabilities"`
+	}
+
+	// PackageSet represents the alignment of a package across all modules
+	// requiring it, Drift is one of the versions.Drift values.
+	PackageSet struct {
+		Name     string   `json:"name"`
+		Same     bool     `json:"same"`
+		Highest  string   `json:"highest"`
+		Drift    string   `json:"drift"`
+		Modules  []string `json:"modules"`
+		Coupling Coupling `json:"coupling"`
+	}
+
+	// Updates represents the newest versions available for a package.
+	Updates struct {
+		Patch string `json:"patch"`
+		Minor string `json:"minor"`
+		Major string `json:"major"`
+	}
+
+	// Vulnerability represents a known vulnerability affecting a package,
+	// Fixed is empty when there is no fix.
+	Vulnerability struct {
+		ID       string   `json:"id"`
+		Aliases  []string `json:"aliases"`
+		Summary  string   `json:"summary"`
+		Severity string   `json:"severity"`
+		Fixed    string   `json:"fixed"`
+	}
+
+	// Workspace represents a parsed go.work file.
+	Workspace struct {
+		Path      string   `json:"path"`
+		GoVersion string   `json:"goVersion"`
+		Toolchain string   `json:"toolchain"`
+		Modules   []string `json:"modules"`
+	}
+
+	//-
+
+	// JSON renders versions as JSON.
+	JSON struct {
+		versions versions.Versions
+		indent   bool
+	}
+
+	// Option is configuration option for this renderer.
+	Option func(*JSON)
+)
+
+const (
+	// SchemaVersion indicates the version of the rendered Document, it is
+	// increased every time a backwards incompatible change is introduced.
+	SchemaVersion = 1
+)
+
+// Decode reads a Document, like the ones rendered by JSON, and returns the
+// versions it describes, allowing rendered documents to be used as snapshots;
+// the module graphs and the metrics are not part of the Document.
+func Decode(r io.Reader) (versions.Versions, error) {
+	var doc Document
+
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return versions.Versions{}, err
+	}
+
+	if doc.SchemaVersion != SchemaVersion {
+		return versions.Versions{}, fmt.Errorf("unsupported schema version %d", doc.SchemaVersion)
+	}
+
+	res := versions.Versions{
+		Modules: make(map[versions.ModuleName]versions.Module, len(doc.Modules)),
+	}
+
+	for _, workspace := range doc.Workspaces {
+		w := versions.Workspace{
+			Path:      workspace.Path,
+			GoVersion: versions.GoVersion(workspace.GoVersion),
+			Toolchain: workspace.Toolchain,
+		}
+
+		for _, name := range workspace.Modules {
+			w.Modules = append(w.Modules, versions.ModuleName(name))
+		}
+
+		res.Workspaces = append(res.Workspaces, w)
+	}
+
+	for _, m := range doc.Modules {
+		mod := versions.Module{
+			ModuleGoVersion: versions.ModuleGoVersion{
+				Name:      versions.ModuleName(m.Name),
+				GoVersion: versions.GoVersion(m.GoVersion),
+			},
+			DependencyRequirements: make(map[versions.PackageName]versions.Package, len(m.Packages)),
+		}
+
+		res.GoVersions.Set(mod.Name, mod.GoVersion)
+
+		for _, p := range m.Packages {
+			pkg := versions.Package{
+				Name:            versions.PackageName(p.Name),
+				Version:         p.Version,
+				SelectedVersion: p.SelectedVersion,
+				IsIndirect:      p.IsIndirect,
+				ReplacedPath:    p.ReplacedPath,
+				ReplacedVersion: p.ReplacedVersion,
+				License: versions.License{
+					Identifier: p.License.Identifier,
+					Name:       p.License.Name,
+					ShortName:  p.License.ShortName,
+					Type:       diligent.Type(p.License.Type),
+					Category:   diligent.Category(p.License.Category),
+					Status:     versions.LicenseStatus(p.License.Status),
+					Error:      p.License.Error,
+					Confidence: p.License.Confidence,
+				},
+				Updates: versions.Updates{
+					Patch: p.Updates.Patch,
+					Minor: p.Updates.Minor,
+					Major: p.Updates.Major,
+				},
+			}
+
+			for _, vuln := range p.Vulnerabilities {
+				pkg.Vulnerabilities = append(pkg.Vulnerabilities, versions.Vulnerability{
+					ID:       vuln.ID,
+					Aliases:  vuln.Aliases,
+					Summary:  vuln.Summary,
+					Severity: vuln.Severity,
+					Fixed:    vuln.Fixed,
+				})
+			}
+
+			mod.DependencyRequirements[pkg.Name] = pkg
+
+			res.Packages.Set(mod.Name, pkg)
+		}
+
+		res.Modules[mod.Name] = mod
+	}
+
+	return res, nil
+}
+
+// NewJSON instantiates a new template for rendering in JSON.
+func NewJSON(v versions.Versions, opts ...Option) JSON {
+	j := JSON{
+		versions: v,
+	}
+
+	for _, opt := range opts {
+		opt(&j)
+	}
+
+	return j
+}
+
+// WithIndent allows indenting the rendered JSON.
+func WithIndent(opt bool) Option {
+	return func(j *JSON) {
+		j.indent = opt
+	}
+}
+
+func init() {
+	versions.RegisterRenderer("json", func(v versions.Versions, _ versions.RendererOptions) versions.Renderer {
+		return NewJSON(v, WithIndent(true))
+	})
+}
+
+func newCoupling(c versions.Coupling) Coupling {
+	return Coupling{
+		Afferent:    c.Afferent,
+		Efferent:    c.Efferent,
+		Instability: c.Instability(),
+	}
+}
+
+func newModule(mod versions.Module, coupling versions.Coupling) Module {
+	res := Module{
+		Name:      string(mod.Name),
+		GoVersion: string(mod.GoVersion),
+		Packages:  make([]Package, 0, len(mod.DependencyRequirements)),
+		Coupling:  newCoupling(coupling),
+	}
+
+	for _, pkg := range mod.DependencyRequirements {
+		vulns := make([]Vulnerability, len(pkg.Vulnerabilities))
+
+		for i, vuln := range pkg.Vulnerabilities {
+			vulns[i] = Vulnerability{
+				ID:       vuln.ID,
+				Aliases:  append([]string{}, vuln.Aliases...),
+				Summary:  vuln.Summary,
+				Severity: vuln.Severity,
+				Fixed:    vuln.Fixed,
+			}
+		}
+
+		res.Packages = append(res.Packages, Package{
+			Name:            string(pkg.Name),
+			Version:         pkg.Version,
+			SelectedVersion: pkg.SelectedVersion,
+			IsIndirect:      pkg.IsIndirect,
+			ReplacedPath:    pkg.ReplacedPath,
+			ReplacedVersion: pkg.ReplacedVersion,
+			License: License{
+				Identifier: pkg.License.Identifier,
+				Name:       pkg.License.Name,
+				ShortName:  pkg.License.ShortName,
+				Type:       string(pkg.License.Type),
+				Category:   string(pkg.License.Category),
+				Status:     string(pkg.License.Status),
+				Error:      pkg.License.Error,
+				Confidence: pkg.License.Confidence,
+			},
+			Updates: Updates{
+				Patch: pkg.Updates.Patch,
+				Minor: pkg.Updates.Minor,
+				Major: pkg.Updates.Major,
+			},
+			Vulnerabilities: vulns,
+		})
+	}
+
+	sort.Slice(res.Packages, func(i, j int) bool {
+		return res.Packages[i].Name < res.Packages[j].Name
+	})
+
+	return res
+}
+
+// Document returns versions as the Document to be rendered, modules and
+// packages are sorted alphabetically by their name.
+func (j JSON) Document() Document {
+	doc := Document{
+		SchemaVersion: SchemaVersion,
+		GoVersions: GoVersions{
+			Same: j.versions.GoVersions.IsSame(),
+		},
+		Modules:    make([]Module, 0, len(j.versions.Modules)),
+		Packages:   []PackageSet{},
+		Workspaces: make([]Workspace, len(j.versions.Workspaces)),
+	}
+
+	for i, workspace := range j.versions.Workspaces {
+		doc.Workspaces[i] = Workspace{
+			Path:      workspace.Path,
+			GoVersion: string(workspace.GoVersion),
+			Toolchain: workspace.Toolchain,
+			Modules:   make([]string, len(workspace.Modules)),
+		}
+
+		for k, name := range workspace.Modules {
+			doc.Workspaces[i].Modules[k] = string(name)
+		}
+	}
+
+	for _, mod := range j.versions.Modules {
+		doc.Modules = append(doc.Modules, newModule(mod, j.versions.ModuleCoupling(mod.Name)))
+	}
+
+	sort.Slice(doc.Modules, func(i, j int) bool {
+		return doc.Modules[i].Name < doc.Modules[j].Name
+	})
+
+	for _, name := range j.versions.Packages.Names() {
+		set := PackageSet{
+			Name:     string(name),
+			Same:     j.versions.Packages.IsSame(name),
+			Highest:  j.versions.Packages.Highest(name),
+			Drift:    string(j.versions.Packages.Drift(name)),
+			Modules:  []string{},
+			Coupling: newCoupling(j.versions.PackageCoupling(name)),
+		}
+
+		for mod := range j.versions.Packages.Values(name) {
+			set.Modules = append(set.Modules, string(mod))
+		}
+
+		sort.Strings(set.Modules)
+
+		doc.Packages = append(doc.Packages, set)
+	}
+
+	sort.Slice(doc.Packages, func(i, j int) bool {
+		return doc.Packages[i].Name < doc.Packages[j].Name
+	})
+
+	return doc
+}
+
+// Render writes versions in JSON format to w.
+func (j JSON) Render(w io.Writer) error {
+	var (
+		data []byte
+		err  error
+	)
+
+	doc := j.Document()
+
+	if j.indent {
+		data, err = json.MarshalIndent(doc, "", "  ")
+	} else {
+		data, err = json.Marshal(doc)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(data)
+
+	return err
+}
+
+// String returns versions in JSON format.
+func (j JSON) String() string {
+	var b strings.Builder
+
+	if err := j.Render(&b); err != nil {
+		return ""
+	}
+
+	return b.String()
+}
@@ -0,0 +1,470 @@
+package json
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/senseyeio/diligent"
+
+	"github.com/MarioCarrion/versions"
+)
+
+func Test_Decode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+		err   bool
+	}{
+		{
+			"OK",
+			NewJSON(newVersions()).String(),
+			false,
+		},
+		{
+			"ERR: invalid JSON",
+			"{",
+			true,
+		},
+		{
+			"ERR: unsupported schema version",
+			`{"schemaVersion": 2}`,
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Decode(strings.NewReader(test.input))
+			if (err != nil) != test.err {
+				t.Fatalf("expected error %t, got %v", test.err, err)
+			}
+
+			if test.err {
+				return
+			}
+
+			expected := newVersions()
+
+			if !cmp.Equal(got.Modules, expected.Modules, cmpopts.EquateEmpty()) {
+				t.Fatalf("expected values do not match: %s", cmp.Diff(got.Modules, expected.Modules, cmpopts.EquateEmpty()))
+			}
+
+			if !cmp.Equal(got.Workspaces, expected.Workspaces) {
+				t.Fatalf("expected values do not match: %s", cmp.Diff(got.Workspaces, expected.Workspaces))
+			}
+
+			if got.GoVersions.IsSame() || got.Packages.IsSame("pkg2") || !got.Packages.IsSame("pkg1") {
+				t.Fatalf("expected Go versions and packages to be aligned as the original ones")
+			}
+		})
+	}
+}
+
+func Test_JSON_Document(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    versions.Versions
+		expected Document
+	}{
+		{
+			"OK: Empty",
+			versions.Versions{},
+			Document{
+				SchemaVersion: SchemaVersion,
+				Modules:       []Module{},
+				Packages:      []PackageSet{},
+				Workspaces:    []Workspace{},
+			},
+		},
+		{
+			"OK",
+			newVersions(),
+			Document{
+				SchemaVersion: SchemaVersion,
+				Modules: []Module{
+					{
+						Name:      "Module1",
+						GoVersion: "1.15",
+						Packages: []Package{
+							{
+								Name:            "pkg2",
+								Version:         "v1",
+								Vulnerabilities: []Vulnerability{},
+							},
+						},
+						Coupling: Coupling{Efferent: 1, Instability: 1},
+					},
+					{
+						Name:      "Module2",
+						GoVersion: "1.14",
+						Packages: []Package{
+							{
+								Name:    "pkg1",
+								Version: "v1",
+								License: License{
+									Identifier: "MIT",
+									Name:       "MIT License",
+									ShortName:  "MIT",
+									Type:       "open source",
+									Category:   "permissive",
+									Status:     "detected",
+									Confidence: 0.5,
+								},
+								Updates: Updates{
+									Patch: "v1.0.1",
+								},
+								Vulnerabilities: []Vulnerability{
+									{
+										ID:       "GO-2021-0001",
+										Aliases:  []string{"CVE-2021-0001"},
+										Summary:  "Denial of service in pkg1",
+										Severity: "HIGH",
+										Fixed:    "v1.0.1",
+									},
+								},
+							},
+							{
+								Name:            "pkg2",
+								Version:         "v2",
+								SelectedVersion: "v2",
+								IsIndirect:      true,
+								ReplacedPath:    "replaced/pkg2",
+								ReplacedVersion: "v3",
+								License: License{
+									Status: "error",
+									Error:  "permission denied",
+								},
+								Vulnerabilities: []Vulnerability{},
+							},
+						},
+						Coupling: Coupling{Efferent: 2, Instability: 1},
+					},
+				},
+				Packages: []PackageSet{
+					{
+						Name:     "pkg1",
+						Same:     true,
+						Highest:  "v1",
+						Modules:  []string{"Module2"},
+						Coupling: Coupling{Afferent: 1},
+					},
+					{
+						Name:     "pkg2",
+						Highest:  "v2",
+						Drift:    "major",
+						Modules:  []string{"Module1", "Module2"},
+						Coupling: Coupling{Afferent: 2},
+					},
+				},
+				Workspaces: []Workspace{
+					{
+						Path:      "go.work",
+						GoVersion: "1.21.3",
+						Toolchain: "go1.21.5",
+						Modules:   []string{"Module1", "Module2"},
+					},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := NewJSON(test.input).Document(); !cmp.Equal(got, test.expected) {
+				t.Fatalf("expected values do not match: %s", cmp.Diff(got, test.expected))
+			}
+		})
+	}
+}
+
+func Test_JSON_String(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    []Option
+		expected string
+	}{
+		{
+			"OK",
+			nil,
+			`{"schemaVersion":1,"goVersions":{"same":false},"modules":[{"name":"Module1","goVersion":"1.15","packages":[{"name":"pkg2","version":"v1","selectedVersion":"","indirect":false,"replacedPath":"","replacedVersion":"","license":{"identifier":"","name":"","shortName":"","type":"","category":"","status":"","error":"","confidence":0},"updates":{"patch":"","minor":"","major":""},"vulnerabilities":[]}],"coupling":{"afferent":0,"efferent":1,"instability":1}},{"name":"Module2","goVersion":"1.14","packages":[{"name":"pkg1","version":"v1","selectedVersion":"","indirect":false,"replacedPath":"","replacedVersion":"","license":{"identifier":"MIT","name":"MIT License","shortName":"MIT","type":"open source","category":"permissive","status":"detected","error":"","confidence":0.5},"updates":{"patch":"v1.0.1","minor":"","major":""},"vulnerabilities":[{"id":"GO-2021-0001","aliases":["CVE-2021-0001"],"summary":"Denial of service in pkg1","severity":"HIGH","fixed":"v1.0.1"}]},{"name":"pkg2","version":"v2","selectedVersion":"v2","indirect":true,"replacedPath":"replaced/pkg2","replacedVersion":"v3","license":{"identifier":"","name":"","shortName":"","type":"","category":"","status":"error","error":"permission denied","confidence":0},"updates":{"patch":"","minor":"","major":""},"vulnerabilities":[]}],"coupling":{"afferent":0,"efferent":2,"instability":1}}],"packages":[{"name":"pkg1","same":true,"highest":"v1","drift":"","modules":["Module2"],"coupling":{"afferent":1,"efferent":0,"instability":0}},{"name":"pkg2","same":false,"highest":"v2","drift":"major","modules":["Module1","Module2"],"coupling":{"afferent":2,"efferent":0,"instability":0}}],"workspaces":[{"path":"go.work","goVersion":"1.21.3","toolchain":"go1.21.5","modules":["Module1","Module2"]}]}`,
+		},
+		{
+			"OK: WithIndent",
+			[]Option{WithIndent(true)},
+			`{
+  "schemaVersion": 1,
+  "goVersions": {
+    "same": false
+  },
+  "modules": [
+    {
+      "name": "Module1",
+      "goVersion": "1.15",
+      "packages": [
+        {
+          "name": "pkg2",
+          "version": "v1",
+          "selectedVersion": "",
+          "indirect": false,
+          "replacedPath": "",
+          "replacedVersion": "",
+          "license": {
+            "identifier": "",
+            "name": "",
+            "shortName": "",
+            "type": "",
+            "category": "",
+            "status": "",
+            "error": "",
+            "confidence": 0
+          },
+          "updates": {
+            "patch": "",
+            "minor": "",
+            "major": ""
+          },
+          "vulnerabilities": []
+        }
+      ],
+      "coupling": {
+        "afferent": 0,
+        "efferent": 1,
+        "instability": 1
+      }
+    },
+    {
+      "name": "Module2",
+      "goVersion": "1.14",
+      "packages": [
+        {
+          "name": "pkg1",
+          "version": "v1",
+          "selectedVersion": "",
+          "indirect": false,
+          "replacedPath": "",
+          "replacedVersion": "",
+          "license": {
+            "identifier": "MIT",
+            "name": "MIT License",
+            "shortName": "MIT",
+            "type": "open source",
+            "category": "permissive",
+            "status": "detected",
+            "error": "",
+            "confidence": 0.5
+          },
+          "updates": {
+            "patch": "v1.0.1",
+            "minor": "",
+            "major": ""
+          },
+          "vulnerabilities": [
+            {
+              "id": "GO-2021-0001",
+              "aliases": [
+                "CVE-2021-0001"
+              ],
+              "summary": "Denial of service in pkg1",
+              "severity": "HIGH",
+              "fixed": "v1.0.1"
+            }
+          ]
+        },
+        {
+          "name": "pkg2",
+          "version": "v2",
+          "selectedVersion": "v2",
+          "indirect": true,
+          "replacedPath": "replaced/pkg2",
+          "replacedVersion": "v3",
+          "license": {
+            "identifier": "",
+            "name": "",
+            "shortName": "",
+            "type": "",
+            "category": "",
+            "status": "error",
+            "error": "permission denied",
+            "confidence": 0
+          },
+          "updates": {
+            "patch": "",
+            "minor": "",
+            "major": ""
+          },
+          "vulnerabilities": []
+        }
+      ],
+      "coupling": {
+        "afferent": 0,
+        "efferent": 2,
+        "instability": 1
+      }
+    }
+  ],
+  "packages": [
+    {
+      "name": "pkg1",
+      "same": true,
+      "highest": "v1",
+      "drift": "",
+      "modules": [
+        "Module2"
+      ],
+      "coupling": {
+        "afferent": 1,
+        "efferent": 0,
+        "instability": 0
+      }
+    },
+    {
+      "name": "pkg2",
+      "same": false,
+      "highest": "v2",
+      "drift": "major",
+      "modules": [
+        "Module1",
+        "Module2"
+      ],
+      "coupling": {
+        "afferent": 2,
+        "efferent": 0,
+        "instability": 0
+      }
+    }
+  ],
+  "workspaces": [
+    {
+      "path": "go.work",
+      "goVersion": "1.21.3",
+      "toolchain": "go1.21.5",
+      "modules": [
+        "Module1",
+        "Module2"
+      ]
+    }
+  ]
+}`,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			j := NewJSON(newVersions(), test.input...)
+
+			if got := j.String(); got != test.expected {
+				t.Fatalf("expected values do not match: %s", cmp.Diff(got, test.expected))
+			}
+
+			var b strings.Builder
+
+			if err := j.Render(&b); err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+
+			if got := b.String(); got != test.expected {
+				t.Fatalf("expected values do not match: %s", cmp.Diff(got, test.expected))
+			}
+		})
+	}
+}
+
+func newVersions() versions.Versions {
+	modules := map[versions.ModuleName]versions.Module{
+		"Module2": {
+			ModuleGoVersion: versions.ModuleGoVersion{Name: "Module2", GoVersion: "1.14"},
+			DependencyRequirements: map[versions.PackageName]versions.Package{
+				"pkg1": {
+					Name:    "pkg1",
+					Version: "v1",
+					License: versions.License{
+						Identifier: "MIT",
+						Name:       "MIT License",
+						ShortName:  "MIT",
+						Type:       diligent.OpenSource,
+						Category:   diligent.Permissive,
+						Status:     versions.LicenseStatusDetected,
+						Confidence: 0.5,
+					},
+					Updates: versions.Updates{
+						Patch: "v1.0.1",
+					},
+					Vulnerabilities: []versions.Vulnerability{
+						{
+							ID:       "GO-2021-0001",
+							Aliases:  []string{"CVE-2021-0001"},
+							Summary:  "Denial of service in pkg1",
+							Severity: "HIGH",
+							Fixed:    "v1.0.1",
+						},
+					},
+				},
+				"pkg2": {
+					Name:            "pkg2",
+					Version:         "v2",
+					SelectedVersion: "v2",
+					IsIndirect:      true,
+					ReplacedPath:    "replaced/pkg2",
+					ReplacedVersion: "v3",
+					License: versions.License{
+						Status: versions.LicenseStatusError,
+						Error:  "permission denied",
+					},
+				},
+			},
+		},
+		"Module1": {
+			ModuleGoVersion: versions.ModuleGoVersion{Name: "Module1", GoVersion: "1.15"},
+			DependencyRequirements: map[versions.PackageName]versions.Package{
+				"pkg2": {
+					Name:    "pkg2",
+					Version: "v1",
+				},
+			},
+		},
+	}
+
+	res := versions.Versions{
+		Modules: modules,
+		Workspaces: []versions.Workspace{
+			{
+				Path:      "go.work",
+				GoVersion: "1.21.3",
+				Toolchain: "go1.21.5",
+				Modules:   []versions.ModuleName{"Module1", "Module2"},
+			},
+		},
+	}
+
+	for _, name := range []versions.ModuleName{"Module2", "Module1"} {
+		mod := modules[name]
+
+		res.GoVersions.Set(mod.Name, mod.GoVersion)
+
+		for _, pkgName := range []versions.PackageName{"pkg2", "pkg1"} {
+			if pkg, ok := mod.DependencyRequirements[pkgName]; ok {
+				res.Packages.Set(mod.Name, pkg)
+			}
+		}
+	}
+
+	return res
+}
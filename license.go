@@ -0,0 +1,233 @@
+package versions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/go-enry/go-license-detector/v4/licensedb"
+	"github.com/go-enry/go-license-detector/v4/licensedb/api"
+	"github.com/go-enry/go-license-detector/v4/licensedb/filer"
+	"github.com/senseyeio/diligent"
+)
+
+type (
+	// licenseCache persists detected licenses in dir, indexed by the path of
+	// the module directory relative to modCache; only "module@version"
+	// directories are cached because their contents are immutable.
+	licenseCache struct {
+		dir      string
+		modCache string
+	}
+)
+
+const (
+	// licenseCacheVersion is part of the cache paths, it must be changed any
+	// time the License type changes.
+	licenseCacheVersion = "v2"
+)
+
+// DefaultLicenseCacheDir returns the default directory used to persist the
+// detected licenses, it is located in the user cache directory.
+func DefaultLicenseCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "versions", "licenses"), nil
+}
+
+// newLicense returns the license detected in path, the Status indicates
+// the reason when it could not be detected.
+func newLicense(path string) License {
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return License{Status: LicenseStatusNotDownloaded}
+		}
+
+		return License{Status: LicenseStatusError, Error: err.Error()}
+	}
+
+	filer, err := filer.FromDirectory(path)
+	if err != nil {
+		return License{Status: LicenseStatusError, Error: err.Error()}
+	}
+
+	licenses, err := licensedb.Detect(filer)
+	if err != nil {
+		if errors.Is(err, licensedb.ErrNoLicenseFound) {
+			return License{Status: LicenseStatusNotFound}
+		}
+
+		return License{Status: LicenseStatusError, Error: err.Error()}
+	}
+
+	var (
+		match api.Match
+		name  string
+	)
+
+	for k, v := range licenses {
+		// Ties are broken by name, to keep results deterministic.
+		if name == "" || v.Confidence > match.Confidence || (v.Confidence == match.Confidence && k < name) {
+			match = v
+			name = k
+		}
+	}
+
+	if name == "" {
+		return License{Status: LicenseStatusNotFound}
+	}
+
+	license, err := diligent.GetLicenseFromIdentifier(name)
+	if err != nil {
+		return License{
+			Identifier: name,
+			Status:     LicenseStatusUnrecognized,
+			Confidence: match.Confidence,
+		}
+	}
+
+	return License{
+		Identifier: license.Identifier,
+		Name:       license.Name,
+		ShortName:  license.ShortName,
+		Type:       license.Type,
+		Category:   license.Category,
+		Status:     LicenseStatusDetected,
+		Confidence: match.Confidence,
+	}
+}
+
+// newLicenses detects the licenses of the paths using up to concurrency
+// workers, the results are indexed by path and therefore do not depend on the
+// order the workers complete; ctx cancellation stops any pending detection.
+// Cached licenses are used when available, otherwise detected ones are cached.
+func newLicenses(ctx context.Context, cache licenseCache, paths []string, concurrency int) (map[string]License, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		res = make(map[string]License, len(paths))
+	)
+
+	jobs := make(chan string)
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for path := range jobs {
+				license, ok := cache.Get(path)
+				if !ok {
+					license = newLicense(path)
+					cache.Put(path, license)
+				}
+
+				mu.Lock()
+				res[path] = license
+				mu.Unlock()
+			}
+		}()
+	}
+
+	var err error
+
+loop:
+	for _, path := range paths {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+			break loop
+		case jobs <- path:
+		}
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Get returns the cached license of the module directory, any error reading
+// the cache is considered a miss.
+func (c licenseCache) Get(path string) (License, bool) {
+	file, ok := c.file(path)
+	if !ok {
+		return License{}, false
+	}
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return License{}, false
+	}
+
+	var license License
+
+	if err := json.Unmarshal(data, &license); err != nil {
+		return License{}, false
+	}
+
+	return license, true
+}
+
+// Put caches the license of the module directory, the cache is a best effort
+// and therefore errors are ignored; the file is written atomically to support
+// concurrent processes. Licenses of modules not downloaded yet, or failing to
+// be detected, are not cached.
+func (c licenseCache) Put(path string, license License) {
+	if license.Status == LicenseStatusNotDownloaded || license.Status == LicenseStatusError {
+		return
+	}
+
+	file, ok := c.file(path)
+	if !ok {
+		return
+	}
+
+	data, err := json.Marshal(license)
+	if err != nil {
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return
+	}
+
+	_ = writeFile(file, data)
+}
+
+// file returns the cache file of the module directory, false is returned
+// when the cache is disabled or the directory is not a "module@version" one
+// in the module cache, like local directories used by replace directives.
+func (c licenseCache) file(path string) (string, bool) {
+	if c.dir == "" {
+		return "", false
+	}
+
+	rel, err := filepath.Rel(c.modCache, path)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") || !strings.Contains(filepath.Base(rel), "@") {
+		return "", false
+	}
+
+	return filepath.Join(c.dir, licenseCacheVersion, rel+".json"), true
+}
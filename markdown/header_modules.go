@@ -37,6 +37,19 @@ func newHeader(sorting ModulesSorting, same bool, values []versions.Module) head
 	}
 }
 
+func (h header) Coupling(v versions.Versions) []string {
+	res := make([]string, len(h.modules)+1)
+
+	res[0] = "Coupling"
+
+	for i, mod := range h.modules {
+		coupling := v.ModuleCoupling(mod.Name)
+		res[i+1] = fmt.Sprintf("Ce %d, Ca %d<br>I %.2f", coupling.Efferent, coupling.Afferent, coupling.Instability())
+	}
+
+	return res
+}
+
 func (h header) GoVersions() []string {
 	res := make([]string, len(h.modules)+1)
 
@@ -3,7 +3,10 @@
 package markdown
 
 import (
-	"os"
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
 
 	"github.com/olekukonko/tablewriter"
 
@@ -25,6 +28,10 @@ type (
 		modulesSortBy       ModulesSorting
 		packagesSortBy      PackagesSorting
 		packagesShowLicense bool
+		packagesShowUpdates bool
+		packagesShowVulns   bool
+		showCoupling        bool
+		showDrift           bool
 	}
 
 	// Option is configuration option for this renderer.
@@ -70,6 +77,24 @@ func NewMarkdown(v versions.Versions, opts ...Option) Markdown {
 	return md
 }
 
+// WithCoupling allows displaying the coupling metrics: a row with the
+// efferent and afferent coupling, and instability, of each module and a column
+// with the afferent coupling of each package.
+func WithCoupling(opt bool) Option {
+	return func(m *Markdown) {
+		m.showCoupling = opt
+	}
+}
+
+// WithDriftSeverity allows marking the versions of packages not used
+// consistently with an emoji indicating how far they are from the highest
+// one: patch, minor, pseudo-version or major.
+func WithDriftSeverity(opt bool) Option {
+	return func(m *Markdown) {
+		m.showDrift = opt
+	}
+}
+
 // WithModulesSorting allows specifyig the sorting option for modules.
 func WithModulesSorting(opt ModulesSorting) Option {
 	return func(m *Markdown) {
@@ -91,8 +116,37 @@ func WithPackagesSorting(opt PackagesSorting) Option {
 	}
 }
 
-// String returns versions in Markdown format.
-func (m Markdown) String() string {
+// WithPackagesUpdates allows displaying the newest versions available for
+// each package, when present.
+func WithPackagesUpdates(opt bool) Option {
+	return func(m *Markdown) {
+		m.packagesShowUpdates = opt
+	}
+}
+
+// WithPackagesVulnerabilities allows displaying the known vulnerabilities
+// affecting each package, when present.
+func WithPackagesVulnerabilities(opt bool) Option {
+	return func(m *Markdown) {
+		m.packagesShowVulns = opt
+	}
+}
+
+func init() {
+	versions.RegisterRenderer("markdown", func(v versions.Versions, o versions.RendererOptions) versions.Renderer {
+		return NewMarkdown(v,
+			WithCoupling(o.Coupling),
+			WithDriftSeverity(true),
+			WithModulesSorting(ModulesSortingAlphabetically),
+			WithPackagesSorting(PackagesSortingAlphabeticallySupported),
+			WithPackagesLicense(true),
+			WithPackagesUpdates(true),
+			WithPackagesVulnerabilities(true))
+	})
+}
+
+// Render writes versions in Markdown format to w.
+func (m Markdown) Render(w io.Writer) error {
 	mods := make([]versions.Module, len(m.versions.Modules))
 	index := 0
 
@@ -102,15 +156,32 @@ func (m Markdown) String() string {
 	}
 
 	header := newHeader(m.modulesSortBy, m.versions.GoVersions.IsSame(), mods)
-	pkgs := newPackages(m.versions, header.modules, m.packagesSortBy, m.packagesShowLicense)
+	pkgs := newPackages(m.versions, header.modules, m.packagesSortBy, m.packagesShowLicense, m.packagesShowUpdates, m.showDrift, m.packagesShowVulns)
+
+	names := header.Names()
+	data := [][]string{header.GoVersions()}
+	rows := pkgs.Values()
+
+	if m.showCoupling {
+		data = append(data, header.Coupling(m.versions))
+
+		for i := range data {
+			data[i] = append(data[i], "")
+		}
+
+		for i, name := range pkgs.Names() {
+			rows[i] = append(rows[i], strconv.Itoa(m.versions.PackageCoupling(name).Afferent))
+		}
+
+		names = append(names, "Afferent")
+	}
 
-	var data [][]string
+	data = append(data, rows...)
 
-	data = append(data, header.GoVersions())
-	data = append(data, pkgs.Values()...)
+	var buf bytes.Buffer
 
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader(header.Names())
+	table := tablewriter.NewWriter(&buf)
+	table.SetHeader(names)
 	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
 	table.SetCenterSeparator("|")
 	table.SetAutoFormatHeaders(false)
@@ -120,5 +191,18 @@ func (m Markdown) String() string {
 	table.AppendBulk(data)
 	table.Render()
 
-	return ""
+	_, err := buf.WriteTo(w)
+
+	return err
+}
+
+// String returns versions in Markdown format.
+func (m Markdown) String() string {
+	var b strings.Builder
+
+	if err := m.Render(&b); err != nil {
+		return ""
+	}
+
+	return b.String()
 }
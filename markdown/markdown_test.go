@@ -0,0 +1,87 @@
+package markdown_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/MarioCarrion/versions"
+	"github.com/MarioCarrion/versions/markdown"
+)
+
+type (
+	failingWriter struct{}
+)
+
+func Test_Markdown_Render(t *testing.T) {
+	t.Parallel()
+
+	v := versions.Versions{
+		Modules: map[versions.ModuleName]versions.Module{
+			"Module2": {
+				ModuleGoVersion: versions.ModuleGoVersion{Name: "Module2", GoVersion: "1.15"},
+				DependencyRequirements: map[versions.PackageName]versions.Package{
+					"pkg1": {Name: "pkg1", Version: "v1"},
+				},
+			},
+			"Module1": {
+				ModuleGoVersion: versions.ModuleGoVersion{Name: "Module1", GoVersion: "1.15"},
+				DependencyRequirements: map[versions.PackageName]versions.Package{
+					"pkg1": {Name: "pkg1", Version: "v2"},
+				},
+			},
+		},
+	}
+
+	v.GoVersions.Set("Module2", "1.15")
+	v.GoVersions.Set("Module1", "1.15")
+	v.Packages.Set("Module2", versions.Package{Name: "pkg1", Version: "v1"})
+	v.Packages.Set("Module1", versions.Package{Name: "pkg1", Version: "v2"})
+
+	expected := "" +
+		"|                       | Module1 | Module2 |\n" +
+		"|-----------------------|---------|---------|\n" +
+		"| :white_check_mark: Go |    1.15 |    1.15 |\n" +
+		"| pkg1                  | v2      | v1      |\n"
+
+	md := markdown.NewMarkdown(v, markdown.WithModulesSorting(markdown.ModulesSortingAlphabetically))
+
+	var b strings.Builder
+
+	if err := md.Render(&b); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if got := b.String(); got != expected {
+		t.Fatalf("expected values do not match: %s", cmp.Diff(got, expected))
+	}
+
+	if got := md.String(); got != expected {
+		t.Fatalf("expected values do not match: %s", cmp.Diff(got, expected))
+	}
+
+	if err := md.Render(failingWriter{}); err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+
+	expected = "" +
+		"|                       |       Module1        |       Module2        | Afferent |\n" +
+		"|-----------------------|----------------------|----------------------|----------|\n" +
+		"| :white_check_mark: Go |                 1.15 |                 1.15 |          |\n" +
+		"| Coupling              | Ce 1, Ca 0<br>I 1.00 | Ce 1, Ca 0<br>I 1.00 |          |\n" +
+		"| pkg1                  | v2                   | v1                   |        2 |\n"
+
+	md = markdown.NewMarkdown(v,
+		markdown.WithModulesSorting(markdown.ModulesSortingAlphabetically),
+		markdown.WithCoupling(true))
+
+	if got := md.String(); got != expected {
+		t.Fatalf("expected values do not match: %s", cmp.Diff(got, expected))
+	}
+}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("failed")
+}
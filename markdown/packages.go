@@ -11,7 +11,12 @@ import (
 type (
 	packageSet struct {
 		same        bool
+		replaced    bool
 		showLicense bool
+		showUpdates bool
+		showDrift   bool
+		showVulns   bool
+		highest     string
 		Name        versions.PackageName
 		packages    []versions.Package
 	}
@@ -24,14 +29,19 @@ type (
 	}
 )
 
-func newPackages(vs versions.Versions, modules []module, sorting PackagesSorting, showLicense bool) packages {
+func newPackages(vs versions.Versions, modules []module, sorting PackagesSorting, showLicense, showUpdates, showDrift, showVulns bool) packages {
 	var res packages
 
 	for _, name := range vs.Packages.Names() {
 		set := packageSet{
 			Name:        name,
 			showLicense: showLicense,
+			showUpdates: showUpdates,
+			showDrift:   showDrift,
+			showVulns:   showVulns,
+			highest:     vs.Packages.Highest(name),
 			same:        vs.Packages.IsSame(name),
+			replaced:    vs.Packages.Drift(name) == versions.DriftReplaced,
 			packages:    make([]versions.Package, len(modules)),
 		}
 
@@ -60,6 +70,111 @@ func newPackages(vs versions.Versions, modules []module, sorting PackagesSorting
 	return res
 }
 
+// writeDrift writes an emoji indicating the severity of the drift between the
+// version of the package and the highest one: yellow for patch, orange for
+// minor, purple for pseudo-versions and red for major; blue is used when only
+// the replacements are different.
+func writeDrift(b *strings.Builder, pkg versions.Package, highest string, replaced bool) {
+	var emoji string
+
+	drift := versions.VersionDrift(pkg.EffectiveVersion(), highest)
+	if drift == versions.DriftNone && replaced {
+		drift = versions.DriftReplaced
+	}
+
+	switch drift {
+	case versions.DriftPatch:
+		emoji = ":yellow_circle: "
+	case versions.DriftMinor:
+		emoji = ":orange_circle: "
+	case versions.DriftPseudo:
+		emoji = ":purple_circle: "
+	case versions.DriftMajor:
+		emoji = ":red_circle: "
+	case versions.DriftReplaced:
+		emoji = ":large_blue_circle: "
+	}
+
+	b.WriteString(emoji)
+}
+
+func writeLicense(b *strings.Builder, license versions.License) {
+	confidence := func() {
+		if license.Confidence > 0 {
+			fmt.Fprintf(b, " %.0f%%", license.Confidence*100)
+		}
+	}
+
+	switch license.Status {
+	case versions.LicenseStatusDetected, "":
+		if license.Identifier == "" {
+			return
+		}
+
+		b.WriteString("<br>")
+		b.WriteString(string(license.Category))
+		b.WriteString(" ")
+		b.WriteString(license.Name)
+		confidence()
+	case versions.LicenseStatusUnrecognized:
+		b.WriteString("<br>:grey_question: unrecognized ")
+		b.WriteString(license.Identifier)
+		confidence()
+	case versions.LicenseStatusNotFound:
+		b.WriteString("<br>:grey_question: license not found")
+	case versions.LicenseStatusNotDownloaded:
+		b.WriteString("<br>:grey_question: not downloaded")
+	case versions.LicenseStatusError:
+		b.WriteString("<br>:x: ")
+		b.WriteString(license.Error)
+	}
+}
+
+func writeUpdates(b *strings.Builder, updates versions.Updates) {
+	var values []string
+
+	for _, update := range []struct {
+		name    string
+		version string
+	}{
+		{"patch", updates.Patch},
+		{"minor", updates.Minor},
+		{"major", updates.Major},
+	} {
+		if update.version != "" {
+			values = append(values, fmt.Sprintf("%s %s", update.name, update.version))
+		}
+	}
+
+	if len(values) == 0 {
+		return
+	}
+
+	b.WriteString("<br>:arrow_up: ")
+	b.WriteString(strings.Join(values, ", "))
+}
+
+// writeVulnerabilities writes the known vulnerabilities affecting the
+// package, including their severity and the version fixing them, if any.
+func writeVulnerabilities(b *strings.Builder, vulns []versions.Vulnerability) {
+	for _, vuln := range vulns {
+		b.WriteString("<br>:warning: ")
+		b.WriteString(vuln.ID)
+
+		if vuln.Severity != "" {
+			b.WriteString(" ")
+			b.WriteString(vuln.Severity)
+		}
+
+		if vuln.Fixed != "" {
+			b.WriteString(", fixed ")
+			b.WriteString(vuln.Fixed)
+		} else {
+			b.WriteString(", not fixed")
+		}
+	}
+}
+
 func (p packageSet) Values() []string {
 	res := make([]string, len(p.packages)+1)
 
@@ -76,8 +191,17 @@ func (p packageSet) Values() []string {
 	for i, v := range p.packages {
 		var b strings.Builder
 
+		if p.showDrift && !p.same && v.Name != "" {
+			writeDrift(&b, v, p.highest, p.replaced)
+		}
+
 		b.WriteString(v.Version)
 
+		if v.SelectedVersion != "" && v.SelectedVersion != v.Version {
+			b.WriteString("<br>selected ")
+			b.WriteString(v.SelectedVersion)
+		}
+
 		if v.ReplacedPath != "" {
 			b.WriteString(" ")
 			b.WriteString(v.ReplacedPath)
@@ -88,11 +212,16 @@ func (p packageSet) Values() []string {
 			b.WriteString(v.ReplacedVersion)
 		}
 
-		if p.showLicense && v.License.Identifier != "" {
-			b.WriteString("<br>")
-			b.WriteString(string(v.License.Category))
-			b.WriteString(" ")
-			b.WriteString(v.License.Name)
+		if p.showLicense {
+			writeLicense(&b, v.License)
+		}
+
+		if p.showUpdates {
+			writeUpdates(&b, v.Updates)
+		}
+
+		if p.showVulns {
+			writeVulnerabilities(&b, v.Vulnerabilities)
 		}
 
 		res[i+1] = b.String()
@@ -117,6 +246,21 @@ func (p packageSets) Swap(i, j int) {
 
 //-
 
+// Names returns the package names in the same order as Values.
+func (p packages) Names() []versions.PackageName {
+	res := make([]versions.PackageName, 0, len(p.same)+len(p.different))
+
+	for _, val := range p.same {
+		res = append(res, val.Name)
+	}
+
+	for _, val := range p.different {
+		res = append(res, val.Name)
+	}
+
+	return res
+}
+
 func (p packages) Values() [][]string {
 	var res [][]string
 
@@ -2,6 +2,7 @@ package markdown
 
 import (
 	"sort"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -29,13 +30,16 @@ func Test_newPackages(t *testing.T) {
 			packages    []inputPkg
 			modules     inputModules
 			showLicense bool
+			showUpdates bool
+			showDrift   bool
 		}
 	)
 
-	newInput := func(sorting PackagesSorting, showLicense bool) input {
+	newInput := func(sorting PackagesSorting, showLicense, showUpdates bool) input {
 		return input{
 			sorting:     sorting,
 			showLicense: showLicense,
+			showUpdates: showUpdates,
 			modules: inputModules{
 				values: []module{
 					{
@@ -68,11 +72,24 @@ func Test_newPackages(t *testing.T) {
 							"diff": {
 								Name:    "diff",
 								Version: "v2",
+								Updates: versions.Updates{
+									Patch: "v2.0.1",
+									Major: "v3.0.0",
+								},
 							},
 							"adiff": {
 								Name:    "adiff",
 								Version: "v2",
 							},
+							"selected": {
+								Name:    "selected",
+								Version: "v2",
+							},
+							"replaced": {
+								Name:         "replaced",
+								Version:      "v1",
+								ReplacedPath: "../local",
+							},
 						},
 					},
 					"Module2": {
@@ -90,6 +107,15 @@ func Test_newPackages(t *testing.T) {
 								Name:    "adiff",
 								Version: "v1",
 							},
+							"selected": {
+								Name:            "selected",
+								Version:         "v1",
+								SelectedVersion: "v2",
+							},
+							"replaced": {
+								Name:    "replaced",
+								Version: "v1",
+							},
 						},
 					},
 				},
@@ -120,6 +146,10 @@ func Test_newPackages(t *testing.T) {
 					versions.Package{
 						Name:    "diff",
 						Version: "v2",
+						Updates: versions.Updates{
+							Patch: "v2.0.1",
+							Major: "v3.0.0",
+						},
 					},
 				},
 				{
@@ -129,6 +159,13 @@ func Test_newPackages(t *testing.T) {
 						Version: "v2",
 					},
 				},
+				{
+					"Module1",
+					versions.Package{
+						Name:    "selected",
+						Version: "v2",
+					},
+				},
 				{
 					"Module2",
 					versions.Package{
@@ -151,6 +188,29 @@ func Test_newPackages(t *testing.T) {
 						Version: "v1",
 					},
 				},
+				{
+					"Module2",
+					versions.Package{
+						Name:            "selected",
+						Version:         "v1",
+						SelectedVersion: "v2",
+					},
+				},
+				{
+					"Module1",
+					versions.Package{
+						Name:         "replaced",
+						Version:      "v1",
+						ReplacedPath: "../local",
+					},
+				},
+				{
+					"Module2",
+					versions.Package{
+						Name:    "replaced",
+						Version: "v1",
+					},
+				},
 			},
 		}
 	}
@@ -162,42 +222,79 @@ func Test_newPackages(t *testing.T) {
 	}{
 		{
 			"OK: PackagesSortingAsFound",
-			newInput(PackagesSortingAsFound, false),
+			newInput(PackagesSortingAsFound, false, false),
 			[][]string{
 				{":white_check_mark: pkg1", "v1 fixtures/license/valid", "v1 fixtures/license/valid"},
 				{":white_check_mark: abc", "v1", ""},
 				{"diff", "v2", "v1"},
 				{"adiff", "v2", "v1"},
+				{":white_check_mark: selected", "v2", "v1<br>selected v2"},
+				{"replaced", "v1 ../local", "v1"},
 			},
 		},
 		{
 			"OK: PackagesSortingAsFound with License",
-			newInput(PackagesSortingAsFound, true),
+			newInput(PackagesSortingAsFound, true, false),
 			[][]string{
 				{":white_check_mark: pkg1", "v1 fixtures/license/valid", "v1 fixtures/license/valid"},
 				{":white_check_mark: abc", "v1<br>permissive LicenseName", ""},
 				{"diff", "v2", "v1"},
 				{"adiff", "v2", "v1"},
+				{":white_check_mark: selected", "v2", "v1<br>selected v2"},
+				{"replaced", "v1 ../local", "v1"},
+			},
+		},
+		{
+			"OK: PackagesSortingAsFound with Updates",
+			newInput(PackagesSortingAsFound, false, true),
+			[][]string{
+				{":white_check_mark: pkg1", "v1 fixtures/license/valid", "v1 fixtures/license/valid"},
+				{":white_check_mark: abc", "v1", ""},
+				{"diff", "v2<br>:arrow_up: patch v2.0.1, major v3.0.0", "v1"},
+				{"adiff", "v2", "v1"},
+				{":white_check_mark: selected", "v2", "v1<br>selected v2"},
+				{"replaced", "v1 ../local", "v1"},
+			},
+		},
+		{
+			"OK: PackagesSortingAsFound with Drift",
+			func() input {
+				in := newInput(PackagesSortingAsFound, false, false)
+				in.showDrift = true
+
+				return in
+			}(),
+			[][]string{
+				{":white_check_mark: pkg1", "v1 fixtures/license/valid", "v1 fixtures/license/valid"},
+				{":white_check_mark: abc", "v1", ""},
+				{"diff", "v2", ":red_circle: v1"},
+				{"adiff", "v2", ":red_circle: v1"},
+				{":white_check_mark: selected", "v2", "v1<br>selected v2"},
+				{"replaced", ":large_blue_circle: v1 ../local", ":large_blue_circle: v1"},
 			},
 		},
 		{
 			"OK: PackagesSortingAlphabeticallySupported",
-			newInput(PackagesSortingAlphabeticallySupported, false),
+			newInput(PackagesSortingAlphabeticallySupported, false, false),
 			[][]string{
 				{":white_check_mark: abc", "v1", ""},
 				{":white_check_mark: pkg1", "v1 fixtures/license/valid", "v1 fixtures/license/valid"},
+				{":white_check_mark: selected", "v2", "v1<br>selected v2"},
 				{"adiff", "v2", "v1"},
 				{"diff", "v2", "v1"},
+				{"replaced", "v1 ../local", "v1"},
 			},
 		},
 		{
 			"OK: PackagesSortingAlphabetically",
-			newInput(PackagesSortingAlphabetically, false),
+			newInput(PackagesSortingAlphabetically, false, false),
 			[][]string{
 				{":white_check_mark: abc", "v1", ""},
 				{"adiff", "v2", "v1"},
 				{"diff", "v2", "v1"},
 				{":white_check_mark: pkg1", "v1 fixtures/license/valid", "v1 fixtures/license/valid"},
+				{"replaced", "v1 ../local", "v1"},
+				{":white_check_mark: selected", "v2", "v1<br>selected v2"},
 			},
 		},
 	}
@@ -217,7 +314,7 @@ func Test_newPackages(t *testing.T) {
 				Modules:  test.input.modules.dependencies,
 			}
 
-			pkgs := newPackages(versions, test.input.modules.values, test.input.sorting, test.input.showLicense)
+			pkgs := newPackages(versions, test.input.modules.values, test.input.sorting, test.input.showLicense, test.input.showUpdates, test.input.showDrift, false)
 			if got := pkgs.Values(); !cmp.Equal(got, test.expected) {
 				t.Fatalf("expected values do not match: %s", cmp.Diff(got, test.expected))
 			}
@@ -267,3 +364,184 @@ func Test_packageSets_Sort(t *testing.T) {
 		})
 	}
 }
+
+func Test_writeDrift(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    versions.Package
+		replaced bool
+		expected string
+	}{
+		{
+			"OK: none",
+			versions.Package{Version: "v1.2.3"},
+			false,
+			"",
+		},
+		{
+			"OK: none, selected",
+			versions.Package{Version: "v1.0.0", SelectedVersion: "v1.2.3"},
+			false,
+			"",
+		},
+		{
+			"OK: patch",
+			versions.Package{Version: "v1.2.0"},
+			false,
+			":yellow_circle: ",
+		},
+		{
+			"OK: minor",
+			versions.Package{Version: "v1.1.0"},
+			false,
+			":orange_circle: ",
+		},
+		{
+			"OK: pseudo",
+			versions.Package{Version: "v1.2.3-0.20200617184744-03fbc970a7f7"},
+			false,
+			":purple_circle: ",
+		},
+		{
+			"OK: major",
+			versions.Package{Version: "v0.9.0"},
+			false,
+			":red_circle: ",
+		},
+		{
+			"OK: replaced",
+			versions.Package{Version: "v1.2.3", ReplacedPath: "../local"},
+			true,
+			":large_blue_circle: ",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var b strings.Builder
+
+			writeDrift(&b, test.input, "v1.2.3", test.replaced)
+
+			if actual := b.String(); actual != test.expected {
+				t.Fatalf("expected %q, got %q", test.expected, actual)
+			}
+		})
+	}
+}
+
+func Test_writeLicense(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    versions.License
+		expected string
+	}{
+		{
+			"OK: detected",
+			versions.License{
+				Identifier: "MIT",
+				Name:       "MIT License",
+				Category:   diligent.Permissive,
+				Status:     versions.LicenseStatusDetected,
+				Confidence: 0.946,
+			},
+			"<br>permissive MIT License 95%",
+		},
+		{
+			"OK: empty",
+			versions.License{},
+			"",
+		},
+		{
+			"OK: unrecognized",
+			versions.License{
+				Identifier: "Beerware",
+				Status:     versions.LicenseStatusUnrecognized,
+				Confidence: 1,
+			},
+			"<br>:grey_question: unrecognized Beerware 100%",
+		},
+		{
+			"OK: not found",
+			versions.License{
+				Status: versions.LicenseStatusNotFound,
+			},
+			"<br>:grey_question: license not found",
+		},
+		{
+			"OK: not downloaded",
+			versions.License{
+				Status: versions.LicenseStatusNotDownloaded,
+			},
+			"<br>:grey_question: not downloaded",
+		},
+		{
+			"OK: error",
+			versions.License{
+				Status: versions.LicenseStatusError,
+				Error:  "permission denied",
+			},
+			"<br>:x: permission denied",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var b strings.Builder
+
+			writeLicense(&b, test.input)
+
+			if actual := b.String(); actual != test.expected {
+				t.Fatalf("expected %q, actual %q", test.expected, actual)
+			}
+		})
+	}
+}
+
+func Test_writeVulnerabilities(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    []versions.Vulnerability
+		expected string
+	}{
+		{
+			"OK: none",
+			nil,
+			"",
+		},
+		{
+			"OK: multiple",
+			[]versions.Vulnerability{
+				{ID: "GO-2021-0001", Severity: "HIGH", Fixed: "v1.2.0"},
+				{ID: "GO-2022-0002"},
+			},
+			"<br>:warning: GO-2021-0001 HIGH, fixed v1.2.0<br>:warning: GO-2022-0002, not fixed",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var b strings.Builder
+
+			writeVulnerabilities(&b, test.input)
+
+			if actual := b.String(); actual != test.expected {
+				t.Fatalf("expected %q, got %q", test.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package versions
+
+type (
+	// Coupling represents the coupling metrics of a Module or Package within
+	// the parsed modules: Afferent is the number of modules requiring it and
+	// Efferent the number of packages it requires.
+	Coupling struct {
+		Afferent int
+		Efferent int
+	}
+)
+
+// Instability returns the ratio of efferent coupling to total coupling,
+// between 0 (maximally stable) and 1 (maximally unstable); 0 is returned when
+// there is no coupling at all.
+func (c Coupling) Instability() float64 {
+	if c.Afferent+c.Efferent == 0 {
+		return 0
+	}
+
+	return float64(c.Efferent) / float64(c.Afferent+c.Efferent)
+}
@@ -0,0 +1,381 @@
+// Package osv implements a client for the Go vulnerability database, using
+// the OSV format, served from a local directory or an HTTP(S) mirror.
+package osv
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/mod/semver"
+)
+
+type (
+	// Affected represents a module affected by an Entry and the ranges of
+	// affected versions.
+	Affected struct {
+		Module Module  `json:"package"`
+		Ranges []Range `json:"ranges"`
+	}
+
+	// Client queries the Go vulnerability database, entries are cached so
+	// each one is only fetched once.
+	Client struct {
+		url        *url.URL
+		httpClient *http.Client
+
+		mu      sync.Mutex
+		index   map[string][]string
+		entries map[string]Entry
+	}
+
+	// DatabaseSpecific represents the database-specific fields of an Entry.
+	DatabaseSpecific struct {
+		URL      string `json:"url"`
+		Severity string `json:"severity"`
+	}
+
+	// Entry represents a vulnerability in OSV format.
+	Entry struct {
+		ID               string           `json:"id"`
+		Aliases          []string         `json:"aliases"`
+		Summary          string           `json:"summary"`
+		Details          string           `json:"details"`
+		Affected         []Affected       `json:"affected"`
+		Severity         []Severity       `json:"severity"`
+		DatabaseSpecific DatabaseSpecific `json:"database_specific"`
+	}
+
+	// Match represents an Entry affecting a module version, Fixed is the
+	// lowest version fixing it, empty when there is no fix.
+	Match struct {
+		Entry Entry
+		Fixed string
+	}
+
+	// Module represents the module affected by an Entry.
+	Module struct {
+		Path      string `json:"name"`
+		Ecosystem string `json:"ecosystem"`
+	}
+
+	// Option is configuration option for the Client.
+	Option func(*Client)
+
+	// Range represents a range of affected versions, only the SEMVER type is
+	// supported.
+	Range struct {
+		Type   string       `json:"type"`
+		Events []RangeEvent `json:"events"`
+	}
+
+	// RangeEvent represents the version introducing or fixing the
+	// vulnerability, versions don't include the "v" prefix and the "0"
+	// introduced version indicates all versions.
+	RangeEvent struct {
+		Introduced string `json:"introduced,omitempty"`
+		Fixed      string `json:"fixed,omitempty"`
+	}
+
+	// Severity represents a severity score, like a CVSS vector.
+	Severity struct {
+		Type  string `json:"type"`
+		Score string `json:"score"`
+	}
+
+	indexModule struct {
+		Path  string `json:"path"`
+		Vulns []struct {
+			ID string `json:"id"`
+		} `json:"vulns"`
+	}
+)
+
+var (
+	// ErrNotFound indicates the file was not found in the database.
+	ErrNotFound = errors.New("not found")
+)
+
+// NewClient returns a Client using the database in source, which is either a
+// local directory, a file:// URL or an HTTP(S) URL, using the layout of
+// https://vuln.go.dev: "index/modules.json" and "ID/$id.json", optionally
+// gzipped.
+func NewClient(source string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(source)
+	if err != nil || u.Scheme == "" || len(u.Scheme) == 1 { // Windows drive letters look like schemes
+		abs, err := filepath.Abs(source)
+		if err != nil {
+			return nil, err
+		}
+
+		u = &url.URL{Scheme: "file", Path: filepath.ToSlash(abs)}
+	}
+
+	switch u.Scheme {
+	case "file", "http", "https":
+	default:
+		return nil, fmt.Errorf("invalid vulnerability database %q: unsupported scheme %q", source, u.Scheme)
+	}
+
+	c := Client{
+		url:        u,
+		httpClient: http.DefaultClient,
+		entries:    make(map[string]Entry),
+	}
+
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	return &c, nil
+}
+
+// WithHTTPClient allows specifying the HTTP client used to query the mirror.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = client
+	}
+}
+
+// Affected returns the fixed version when the module version is in any of the
+// affected ranges, ok is false otherwise.
+func (a Affected) Affected(version string) (fixed string, ok bool) {
+	if len(a.Ranges) == 0 {
+		return "", true
+	}
+
+	for _, r := range a.Ranges {
+		if r.Type != "SEMVER" {
+			continue
+		}
+
+		if fixed, ok := r.affected(version); ok {
+			return fixed, true
+		}
+	}
+
+	return "", false
+}
+
+// Affecting returns the entries affecting the module version, sorted by ID.
+func (c *Client) Affecting(ctx context.Context, path, version string) ([]Match, error) {
+	ids, err := c.ids(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []Match
+
+	for _, id := range ids {
+		entry, err := c.Entry(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if fixed, ok := entry.Affects(path, version); ok {
+			res = append(res, Match{Entry: entry, Fixed: fixed})
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Entry.ID < res[j].Entry.ID
+	})
+
+	return res, nil
+}
+
+// Entry returns the entry with the ID, it corresponds to the "ID/$id.json"
+// endpoint.
+func (c *Client) Entry(ctx context.Context, id string) (Entry, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[id]
+	c.mu.Unlock()
+
+	if ok {
+		return entry, nil
+	}
+
+	data, err := c.fetch(ctx, "ID/"+id)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return Entry{}, fmt.Errorf("invalid entry %s: %w", id, err)
+	}
+
+	c.mu.Lock()
+	c.entries[id] = entry
+	c.mu.Unlock()
+
+	return entry, nil
+}
+
+func (c *Client) fetch(ctx context.Context, name string) ([]byte, error) {
+	data, err := c.get(ctx, name+".json")
+	if !errors.Is(err, ErrNotFound) {
+		return data, err
+	}
+
+	data, err = c.get(ctx, name+".json.gz")
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s.json.gz: %w", name, err)
+	}
+	defer r.Close()
+
+	return ioutil.ReadAll(r)
+}
+
+func (c *Client) get(ctx context.Context, endpoint string) ([]byte, error) {
+	if c.url.Scheme == "file" {
+		data, err := ioutil.ReadFile(filepath.Join(filepath.FromSlash(c.url.Path), filepath.FromSlash(endpoint)))
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%s: %w", endpoint, ErrNotFound)
+		}
+
+		return data, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(c.url.String(), "/")+"/"+endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusGone:
+		return nil, fmt.Errorf("%s: %w", endpoint, ErrNotFound)
+	default:
+		return nil, fmt.Errorf("%s: unexpected status %s", endpoint, resp.Status)
+	}
+
+	return ioutil.ReadAll(resp.Body)
+}
+
+// ids returns the IDs of the entries affecting any version of the module,
+// using the "index/modules.json" endpoint, which is only fetched once.
+func (c *Client) ids(ctx context.Context, path string) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.index == nil {
+		data, err := c.fetch(ctx, "index/modules")
+		if err != nil {
+			return nil, err
+		}
+
+		var modules []indexModule
+
+		if err := json.Unmarshal(data, &modules); err != nil {
+			return nil, fmt.Errorf("invalid modules index: %w", err)
+		}
+
+		c.index = make(map[string][]string, len(modules))
+
+		for _, mod := range modules {
+			for _, vuln := range mod.Vulns {
+				c.index[mod.Path] = append(c.index[mod.Path], vuln.ID)
+			}
+		}
+	}
+
+	return c.index[path], nil
+}
+
+// Affects returns the fixed version when the module version is affected by
+// the entry, ok is false otherwise.
+func (e Entry) Affects(path, version string) (fixed string, ok bool) {
+	for _, affected := range e.Affected {
+		if affected.Module.Path != path {
+			continue
+		}
+
+		if fixed, ok := affected.Affected(version); ok {
+			return fixed, true
+		}
+	}
+
+	return "", false
+}
+
+// SeverityLevel returns the severity of the entry: the database-specific one,
+// like "HIGH", when defined, otherwise the first score, like a CVSS vector.
+func (e Entry) SeverityLevel() string {
+	if e.DatabaseSpecific.Severity != "" {
+		return e.DatabaseSpecific.Severity
+	}
+
+	if len(e.Severity) > 0 {
+		return e.Severity[0].Score
+	}
+
+	return ""
+}
+
+// affected returns the version fixing the range when the version is in it,
+// ok is false otherwise. Events are evaluated in version order, introduced
+// events start an affected interval and fixed events end it.
+func (r Range) affected(version string) (fixed string, ok bool) {
+	events := append([]RangeEvent{}, r.Events...)
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return semver.Compare(events[i].version(), events[j].version()) < 0
+	})
+
+	var affected bool
+
+	for _, event := range events {
+		if semver.Compare(version, event.version()) < 0 {
+			if affected && event.Fixed != "" {
+				return event.version(), true
+			}
+
+			break
+		}
+
+		affected = event.Introduced != ""
+	}
+
+	return "", affected
+}
+
+// version returns the canonical semantic version of the event, "v0.0.0-0",
+// lower than any pseudo-version, is returned for the "0" introduced version.
+func (e RangeEvent) version() string {
+	v := e.Introduced
+	if v == "" {
+		v = e.Fixed
+	}
+
+	if v == "0" {
+		return "v0.0.0-0"
+	}
+
+	if !strings.HasPrefix(v, "v") {
+		v = "v" + v
+	}
+
+	return v
+}
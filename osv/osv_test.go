@@ -0,0 +1,200 @@
+package osv_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/MarioCarrion/versions/osv"
+)
+
+func Test_Affected_Affected(t *testing.T) {
+	t.Parallel()
+
+	affected := osv.Affected{
+		Ranges: []osv.Range{
+			{
+				Type: "SEMVER",
+				Events: []osv.RangeEvent{
+					{Introduced: "1.1.0"},
+					{Fixed: "1.2.0"},
+					{Introduced: "0"},
+					{Fixed: "1.0.5"},
+				},
+			},
+			{
+				Type: "GIT",
+				Events: []osv.RangeEvent{
+					{Introduced: "0"},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		input         string
+		expectedFixed string
+		expectedOK    bool
+	}{
+		{"OK: pseudo-version", "v0.0.0-20191014201558-431d9a760f2d", "v1.0.5", true},
+		{"OK: first range", "v1.0.4", "v1.0.5", true},
+		{"OK: fixed", "v1.0.5", "", false},
+		{"OK: between ranges", "v1.0.9", "", false},
+		{"OK: second range", "v1.1.0", "v1.2.0", true},
+		{"OK: after ranges", "v1.2.1", "", false},
+		{"OK: invalid", "latest", "", false},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			fixed, ok := affected.Affected(test.input)
+			if fixed != test.expectedFixed || ok != test.expectedOK {
+				t.Fatalf("expected %s %t, got %s %t", test.expectedFixed, test.expectedOK, fixed, ok)
+			}
+		})
+	}
+
+	if fixed, ok := (osv.Affected{}).Affected("v1.0.0"); fixed != "" || !ok {
+		t.Fatalf("expected all versions to be affected without ranges, got %s %t", fixed, ok)
+	}
+}
+
+func Test_Client_Affecting(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join("..", "fixtures", "osv")
+
+	server := httptest.NewServer(http.FileServer(http.Dir(dir)))
+	t.Cleanup(server.Close)
+
+	type input struct {
+		path    string
+		version string
+	}
+
+	tests := []struct {
+		name     string
+		input    input
+		expected []string
+		fixed    []string
+	}{
+		{
+			"OK: multiple",
+			input{"github.com/example/vulnerable", "v1.1.3"},
+			[]string{"GO-2021-0001", "GO-2022-0002"},
+			[]string{"v1.2.0", ""},
+		},
+		{
+			"OK: single",
+			input{"github.com/example/vulnerable", "v0.9.0"},
+			[]string{"GO-2021-0001"},
+			[]string{"v1.0.5"},
+		},
+		{
+			"OK: not affected",
+			input{"github.com/example/replacement", "v2.0.1"},
+			nil,
+			nil,
+		},
+		{
+			"OK: unknown module",
+			input{"github.com/example/unknown", "v1.0.0"},
+			nil,
+			nil,
+		},
+	}
+
+	for _, source := range []string{dir, server.URL} {
+		client, err := osv.NewClient(source, osv.WithHTTPClient(server.Client()))
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		for _, test := range tests {
+			matches, err := client.Affecting(context.Background(), test.input.path, test.input.version)
+			if err != nil {
+				t.Fatalf("%s: %s: expected no error, got %s", source, test.name, err)
+			}
+
+			var ids, fixed []string
+
+			for _, match := range matches {
+				ids = append(ids, match.Entry.ID)
+				fixed = append(fixed, match.Fixed)
+			}
+
+			if !cmp.Equal(ids, test.expected) || !cmp.Equal(fixed, test.fixed) {
+				t.Fatalf("%s: %s: expected %v %v, got %v %v", source, test.name, test.expected, test.fixed, ids, fixed)
+			}
+		}
+	}
+}
+
+func Test_Client_Entry(t *testing.T) {
+	t.Parallel()
+
+	client, err := osv.NewClient(filepath.Join("..", "fixtures", "osv"))
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	entry, err := client.Entry(context.Background(), "GO-2022-0002")
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if entry.Summary != "Unfixed information disclosure in github.com/example/vulnerable" {
+		t.Fatalf("unexpected summary %s", entry.Summary)
+	}
+
+	if severity := entry.SeverityLevel(); severity != "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:N/A:N" {
+		t.Fatalf("unexpected severity %s", severity)
+	}
+
+	entry, err = client.Entry(context.Background(), "GO-2021-0001")
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if severity := entry.SeverityLevel(); severity != "HIGH" {
+		t.Fatalf("unexpected severity %s", severity)
+	}
+
+	if _, err := client.Entry(context.Background(), "GO-0000-0000"); err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+}
+
+func Test_NewClient(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+		err   bool
+	}{
+		{"OK: directory", "fixtures/osv", false},
+		{"OK: file", "file:///var/lib/vulndb", false},
+		{"OK: https", "https://vuln.go.dev", false},
+		{"ERR: unsupported scheme", "ftp://vuln.go.dev", true},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := osv.NewClient(test.input); (err != nil) != test.err {
+				t.Fatalf("expected error %t, got %v", test.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,230 @@
+// Package policy evaluates the licenses of the packages used by all modules
+// against allowed and denied SPDX identifiers and categories.
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/senseyeio/diligent"
+
+	"github.com/MarioCarrion/versions"
+)
+
+type (
+	// Action indicates what happens when a license matches a rule.
+	Action string
+
+	// Config defines the license policy, rules are evaluated in the following
+	// order: denied identifiers, allowed identifiers, denied categories, warned
+	// identifiers and categories, and finally allowed categories. When there
+	// are allowed identifiers or categories, licenses not matching any rule are
+	// denied, otherwise they are allowed. Packages without a detected license,
+	// including unrecognized ones not matching an identifier rule, use the
+	// Unknown action, which defaults to ActionWarn.
+	Config struct {
+		Allow   Rules  `json:"allow"`
+		Deny    Rules  `json:"deny"`
+		Warn    Rules  `json:"warn"`
+		Unknown Action `json:"unknown"`
+	}
+
+	// Rules defines the SPDX license identifiers and categories a rule applies
+	// to, identifiers are case insensitive.
+	Rules struct {
+		Identifiers []string            `json:"identifiers"`
+		Categories  []diligent.Category `json:"categories"`
+	}
+
+	// Violation represents a package, required by a module, not complying with
+	// the policy.
+	Violation struct {
+		Module  versions.ModuleName
+		Package versions.Package
+		Action  Action
+		Reason  string
+	}
+
+	// Violations represents all the packages not complying with the policy.
+	Violations []Violation
+)
+
+const (
+	// ActionAllow indicates the license is allowed.
+	ActionAllow Action = "allow"
+
+	// ActionDeny indicates the license is not allowed.
+	ActionDeny Action = "deny"
+
+	// ActionWarn indicates the license is allowed but it should be reviewed.
+	ActionWarn Action = "warn"
+)
+
+// Load returns the policy defined in the JSON file.
+func Load(file string) (Config, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return Config{}, err
+	}
+	defer f.Close()
+
+	var config Config
+
+	dec := json.NewDecoder(f)
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(&config); err != nil {
+		return Config{}, fmt.Errorf("invalid policy %s: %w", file, err)
+	}
+
+	switch config.Unknown {
+	case "":
+		config.Unknown = ActionWarn
+	case ActionAllow, ActionWarn, ActionDeny:
+	default:
+		return Config{}, fmt.Errorf("invalid policy %s: unknown action %q", file, config.Unknown)
+	}
+
+	for _, rules := range []Rules{config.Allow, config.Deny, config.Warn} {
+		if err := rules.validate(); err != nil {
+			return Config{}, fmt.Errorf("invalid policy %s: %w", file, err)
+		}
+	}
+
+	return config, nil
+}
+
+// Evaluate returns the packages, in all modules, not complying with the
+// policy sorted by module and package names.
+func (c Config) Evaluate(v versions.Versions) Violations {
+	var res Violations
+
+	for name, mod := range v.Modules {
+		for _, pkg := range mod.DependencyRequirements {
+			action, reason := c.evaluate(pkg.License)
+			if action == ActionAllow {
+				continue
+			}
+
+			res = append(res, Violation{
+				Module:  name,
+				Package: pkg,
+				Action:  action,
+				Reason:  reason,
+			})
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Module == res[j].Module {
+			return res[i].Package.Name < res[j].Package.Name
+		}
+
+		return res[i].Module < res[j].Module
+	})
+
+	return res
+}
+
+func (c Config) evaluate(license versions.License) (Action, string) {
+	switch {
+	case c.Deny.hasIdentifier(license.Identifier):
+		return ActionDeny, fmt.Sprintf("license %s is denied", license.Identifier)
+	case c.Allow.hasIdentifier(license.Identifier):
+		return ActionAllow, ""
+	case license.Status != versions.LicenseStatusDetected:
+		return c.unknown(license)
+	case c.Deny.hasCategory(license.Category):
+		return ActionDeny, fmt.Sprintf("license %s, category %s, is denied", license.Identifier, license.Category)
+	case c.Warn.hasIdentifier(license.Identifier), c.Warn.hasCategory(license.Category):
+		return ActionWarn, fmt.Sprintf("license %s, category %s, should be reviewed", license.Identifier, license.Category)
+	case c.Allow.hasCategory(license.Category):
+		return ActionAllow, ""
+	case len(c.Allow.Identifiers) > 0 || len(c.Allow.Categories) > 0:
+		return ActionDeny, fmt.Sprintf("license %s, category %s, is not allowed", license.Identifier, license.Category)
+	}
+
+	return ActionAllow, ""
+}
+
+func (c Config) unknown(license versions.License) (Action, string) {
+	action := c.Unknown
+	if action == "" {
+		action = ActionWarn
+	}
+
+	reason := "unknown license"
+
+	switch license.Status {
+	case "":
+	case versions.LicenseStatusUnrecognized:
+		reason = fmt.Sprintf("%s, %s %s", reason, license.Status, license.Identifier)
+	case versions.LicenseStatusError:
+		reason = fmt.Sprintf("%s, %s %s", reason, license.Status, license.Error)
+	default:
+		reason = fmt.Sprintf("%s, %s", reason, license.Status)
+	}
+
+	return action, reason
+}
+
+func (r Rules) hasCategory(category diligent.Category) bool {
+	for _, c := range r.Categories {
+		if c == category {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r Rules) hasIdentifier(identifier string) bool {
+	for _, id := range r.Identifiers {
+		if strings.EqualFold(id, identifier) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r Rules) validate() error {
+	for _, category := range r.Categories {
+		switch category {
+		case diligent.Permissive, diligent.CopyLeft, diligent.CopyLeftLimited, diligent.FreeRestricted,
+			diligent.ProprietaryFree, diligent.PublicDomain:
+		default:
+			return fmt.Errorf("unknown category %q", category)
+		}
+	}
+
+	known := Rules{Identifiers: diligent.GetLicenseIdentifiers()}
+
+	for _, identifier := range r.Identifiers {
+		if !known.hasIdentifier(identifier) {
+			return fmt.Errorf("unknown identifier %q", identifier)
+		}
+	}
+
+	return nil
+}
+
+// String returns the violation as text.
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: %s: %s %s: %s", v.Action, v.Module, v.Package.Name, v.Package.Version, v.Reason)
+}
+
+// HasDenied returns true when at least one of the packages uses a denied
+// license.
+func (v Violations) HasDenied() bool {
+	for _, violation := range v {
+		if violation.Action == ActionDeny {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,214 @@
+package policy_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/senseyeio/diligent"
+
+	"github.com/MarioCarrion/versions"
+	"github.com/MarioCarrion/versions/policy"
+)
+
+func Test_Config_Evaluate(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mit       = versions.License{Identifier: "MIT", Category: diligent.Permissive, Status: versions.LicenseStatusDetected}
+		bsd       = versions.License{Identifier: "BSD-3-Clause", Category: diligent.Permissive, Status: versions.LicenseStatusDetected}
+		gpl       = versions.License{Identifier: "GPL-3.0", Category: diligent.CopyLeft, Status: versions.LicenseStatusDetected}
+		agpl      = versions.License{Identifier: "AGPL-3.0", Category: diligent.CopyLeft, Status: versions.LicenseStatusDetected}
+		mpl       = versions.License{Identifier: "MPL-2.0", Category: diligent.CopyLeftLimited, Status: versions.LicenseStatusDetected}
+		unlicense = versions.License{Identifier: "Unlicense", Category: diligent.PublicDomain, Status: versions.LicenseStatusDetected}
+		beerware  = versions.License{Identifier: "Beerware", Status: versions.LicenseStatusUnrecognized}
+		missing   = versions.License{Status: versions.LicenseStatusNotDownloaded}
+	)
+
+	v := versions.Versions{
+		Modules: map[versions.ModuleName]versions.Module{
+			"one": {
+				DependencyRequirements: map[versions.PackageName]versions.Package{
+					"github.com/mit":      {Name: "github.com/mit", Version: "v1.0.0", License: mit},
+					"github.com/gpl":      {Name: "github.com/gpl", Version: "v1.0.0", License: gpl},
+					"github.com/beerware": {Name: "github.com/beerware", Version: "v1.0.0", License: beerware},
+					"github.com/unknown":  {Name: "github.com/unknown", Version: "v1.0.0", License: missing},
+				},
+			},
+			"two": {
+				DependencyRequirements: map[versions.PackageName]versions.Package{
+					"github.com/agpl":      {Name: "github.com/agpl", Version: "v2.0.0", License: agpl},
+					"github.com/bsd":       {Name: "github.com/bsd", Version: "v1.0.0", License: bsd},
+					"github.com/mpl":       {Name: "github.com/mpl", Version: "v1.0.0", License: mpl},
+					"github.com/unlicense": {Name: "github.com/unlicense", Version: "v1.0.0", License: unlicense},
+				},
+			},
+		},
+	}
+
+	type expected struct {
+		violations []string
+		denied     bool
+	}
+
+	tests := []struct {
+		name     string
+		input    policy.Config
+		expected expected
+	}{
+		{
+			"OK: empty policy",
+			policy.Config{},
+			expected{
+				violations: []string{
+					"warn: one: github.com/beerware v1.0.0: unknown license, unrecognized Beerware",
+					"warn: one: github.com/unknown v1.0.0: unknown license, not-downloaded",
+				},
+			},
+		},
+		{
+			"OK: deny lists",
+			policy.Config{
+				Deny:    policy.Rules{Identifiers: []string{"agpl-3.0", "Beerware"}, Categories: []diligent.Category{diligent.CopyLeft}},
+				Unknown: policy.ActionAllow,
+			},
+			expected{
+				violations: []string{
+					"deny: one: github.com/beerware v1.0.0: license Beerware is denied",
+					"deny: one: github.com/gpl v1.0.0: license GPL-3.0, category copyleft, is denied",
+					"deny: two: github.com/agpl v2.0.0: license AGPL-3.0 is denied",
+				},
+				denied: true,
+			},
+		},
+		{
+			"OK: allow lists",
+			policy.Config{
+				Allow:   policy.Rules{Identifiers: []string{"GPL-3.0"}, Categories: []diligent.Category{diligent.Permissive}},
+				Deny:    policy.Rules{Categories: []diligent.Category{diligent.CopyLeft}},
+				Warn:    policy.Rules{Identifiers: []string{"MPL-2.0"}},
+				Unknown: policy.ActionDeny,
+			},
+			expected{
+				violations: []string{
+					"deny: one: github.com/beerware v1.0.0: unknown license, unrecognized Beerware",
+					"deny: one: github.com/unknown v1.0.0: unknown license, not-downloaded",
+					"deny: two: github.com/agpl v2.0.0: license AGPL-3.0, category copyleft, is denied",
+					"warn: two: github.com/mpl v1.0.0: license MPL-2.0, category copyleft-limited, should be reviewed",
+					"deny: two: github.com/unlicense v1.0.0: license Unlicense, category public-domain, is not allowed",
+				},
+				denied: true,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			violations := test.input.Evaluate(v)
+
+			var actual []string
+			for _, violation := range violations {
+				actual = append(actual, violation.String())
+			}
+
+			if !cmp.Equal(test.expected.violations, actual) {
+				t.Fatalf("expected values do not match: %s", cmp.Diff(test.expected.violations, actual))
+			}
+
+			if test.expected.denied != violations.HasDenied() {
+				t.Fatalf("expected %t, actual %t", test.expected.denied, violations.HasDenied())
+			}
+		})
+	}
+}
+
+func Test_Load(t *testing.T) {
+	t.Parallel()
+
+	type expected struct {
+		config policy.Config
+		err    bool
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected expected
+	}{
+		{
+			"OK",
+			"../fixtures/policy/valid.json",
+			expected{
+				config: policy.Config{
+					Allow: policy.Rules{
+						Identifiers: []string{"BSD-3-Clause"},
+						Categories:  []diligent.Category{diligent.Permissive},
+					},
+					Deny: policy.Rules{
+						Identifiers: []string{"AGPL-3.0"},
+						Categories:  []diligent.Category{diligent.CopyLeft},
+					},
+					Warn: policy.Rules{
+						Categories: []diligent.Category{diligent.CopyLeftLimited},
+					},
+					Unknown: policy.ActionDeny,
+				},
+			},
+		},
+		{
+			"ERR: invalid action",
+			"../fixtures/policy/invalid_action.json",
+			expected{
+				err: true,
+			},
+		},
+		{
+			"ERR: invalid category",
+			"../fixtures/policy/invalid_category.json",
+			expected{
+				err: true,
+			},
+		},
+		{
+			"ERR: invalid identifier",
+			"../fixtures/policy/invalid_identifier.json",
+			expected{
+				err: true,
+			},
+		},
+		{
+			"ERR: invalid field",
+			"../fixtures/policy/invalid_field.json",
+			expected{
+				err: true,
+			},
+		},
+		{
+			"ERR: missing file",
+			"../fixtures/policy/missing.json",
+			expected{
+				err: true,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := policy.Load(test.input)
+			if (err != nil) != test.expected.err {
+				t.Fatalf("expected error %t, actual %s", test.expected.err, err)
+			}
+
+			if !cmp.Equal(test.expected.config, actual) {
+				t.Fatalf("expected values do not match: %s", cmp.Diff(test.expected.config, actual))
+			}
+		})
+	}
+}
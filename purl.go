@@ -0,0 +1,40 @@
+package versions
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PURL returns the package URL, using the "golang" type, of the module path
+// and version; the version is omitted when empty.
+func PURL(path, version string) string {
+	segments := strings.Split(path, "/")
+
+	for i, segment := range segments {
+		segments[i] = escapePURL(segment)
+	}
+
+	res := "pkg:golang/" + strings.Join(segments, "/")
+
+	if version != "" {
+		res += "@" + escapePURL(version)
+	}
+
+	return res
+}
+
+// escapePURL percent-encodes the characters not allowed in purl components.
+func escapePURL(s string) string {
+	var b strings.Builder
+
+	for _, c := range []byte(s) {
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9', c == '.', c == '-', c == '_', c == '~':
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,98 @@
+package versions
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+type (
+	// Renderer defines the interface implemented by all the outputs used for
+	// rendering Versions.
+	Renderer interface {
+		// Render writes the rendered versions to w.
+		Render(w io.Writer) error
+
+		// String returns the rendered versions.
+		String() string
+	}
+
+	// RendererFunc instantiates the Renderer used for rendering the versions,
+	// using the options supported by the Renderer.
+	RendererFunc func(Versions, RendererOptions) Renderer
+
+	// RendererOption configures the Renderer instantiated by NewRenderer.
+	RendererOption func(*RendererOptions)
+
+	// RendererOptions represents the options passed to the RendererFunc.
+	RendererOptions struct {
+		// Coupling indicates the coupling metrics are rendered.
+		Coupling bool
+	}
+)
+
+var (
+	renderers   = make(map[string]RendererFunc)
+	renderersMu sync.RWMutex
+)
+
+// NewRenderer returns the Renderer registered using the format name,
+// configured using the options.
+func NewRenderer(name string, v Versions, opts ...RendererOption) (Renderer, error) {
+	renderersMu.RLock()
+	fn, ok := renderers[name]
+	renderersMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown renderer format %q", name)
+	}
+
+	var options RendererOptions
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return fn(v, options), nil
+}
+
+// RegisterRenderer makes a Renderer available using the format name, it is
+// meant to be called from the init function of the package implementing the
+// Renderer. It panics if the name is already registered or fn is nil.
+func RegisterRenderer(name string, fn RendererFunc) {
+	renderersMu.Lock()
+	defer renderersMu.Unlock()
+
+	if fn == nil {
+		panic("versions: RegisterRenderer func is nil")
+	}
+
+	if _, ok := renderers[name]; ok {
+		panic(fmt.Sprintf("versions: RegisterRenderer called twice for format %q", name))
+	}
+
+	renderers[name] = fn
+}
+
+// Renderers returns the sorted list of the registered format names.
+func Renderers() []string {
+	renderersMu.RLock()
+	defer renderersMu.RUnlock()
+
+	res := make([]string, 0, len(renderers))
+	for name := range renderers {
+		res = append(res, name)
+	}
+
+	sort.Strings(res)
+
+	return res
+}
+
+// WithRendererCoupling allows rendering the coupling metrics.
+func WithRendererCoupling(opt bool) RendererOption {
+	return func(o *RendererOptions) {
+		o.Coupling = opt
+	}
+}
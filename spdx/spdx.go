@@ -0,0 +1,370 @@
+// Package spdx generates SPDX 2.3 software bill of materials, in JSON and
+// tag-value formats, describing the packages required by a module.
+package spdx
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/module"
+
+	"github.com/MarioCarrion/versions"
+)
+
+type (
+	// CreationInfo represents who and when created the Document.
+	CreationInfo struct {
+		Created  string   `json:"created"`
+		Creators []string `json:"creators"`
+	}
+
+	// Document represents a SPDX 2.3 document describing a module, using its
+	// JSON schema.
+	Document struct {
+		SPDXVersion       string         `json:"spdxVersion"`
+		DataLicense       string         `json:"dataLicense"`
+		SPDXID            string         `json:"SPDXID"`
+		Name              string         `json:"name"`
+		DocumentNamespace string         `json:"documentNamespace"`
+		CreationInfo      CreationInfo   `json:"creationInfo"`
+		Packages          []Package      `json:"packages"`
+		Relationships     []Relationship `json:"relationships"`
+	}
+
+	// ExternalRef represents a reference to an external source of information
+	// about a Package, like its purl.
+	ExternalRef struct {
+		ReferenceCategory string `json:"referenceCategory"`
+		ReferenceType     string `json:"referenceType"`
+		ReferenceLocator  string `json:"referenceLocator"`
+	}
+
+	// Package represents the module described by the Document or one of its
+	// dependencies.
+	Package struct {
+		Name             string        `json:"name"`
+		SPDXID           string        `json:"SPDXID"`
+		VersionInfo      string        `json:"versionInfo,omitempty"`
+		DownloadLocation string        `json:"downloadLocation"`
+		FilesAnalyzed    bool          `json:"filesAnalyzed"`
+		LicenseConcluded string        `json:"licenseConcluded"`
+		LicenseDeclared  string        `json:"licenseDeclared"`
+		CopyrightText    string        `json:"copyrightText"`
+		ExternalRefs     []ExternalRef `json:"externalRefs,omitempty"`
+	}
+
+	// Relationship represents how two elements of the Document are related.
+	Relationship struct {
+		SPDXElementID      string `json:"spdxElementId"`
+		RelationshipType   string `json:"relationshipType"`
+		RelatedSPDXElement string `json:"relatedSpdxElement"`
+		Comment            string `json:"comment,omitempty"`
+	}
+
+	//-
+
+	// Option is configuration option for NewDocument.
+	Option func(*options)
+
+	//-
+
+	options struct {
+		created   time.Time
+		namespace string
+	}
+)
+
+const (
+	// NoAssertion indicates no attempt was made to determine the value.
+	NoAssertion = "NOASSERTION"
+
+	// None indicates the value is known to be empty, like the license of a
+	// package not including one.
+	None = "NONE"
+
+	// Version is the supported SPDX version.
+	Version = "SPDX-2.3"
+)
+
+const (
+	documentID = "SPDXRef-DOCUMENT"
+
+	relationshipDependsOn = "DEPENDS_ON"
+	relationshipDescribes = "DESCRIBES"
+)
+
+var (
+	invalidIDChars = regexp.MustCompile(`[^a-zA-Z0-9.-]+`)
+)
+
+// NewDocument returns the Document describing the module and the packages it
+// requires, the license detected for each package is used as declared and
+// concluded license, and its purl as external reference; packages replaced by
+// modules are described using the replacement.
+//
+// The module depends on all its direct requirements. Indirect requirements
+// are depended on by the requirement needing them, when the module graph is
+// known, see versions.WithModuleGraph, otherwise by the module itself using
+// the "indirect" comment.
+func NewDocument(v versions.Versions, name versions.ModuleName, opts ...Option) (Document, error) {
+	mod, ok := v.Modules[name]
+	if !ok {
+		return Document{}, fmt.Errorf("unknown module %s", name)
+	}
+
+	o := options{
+		created: time.Now(),
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.namespace == "" {
+		b := make([]byte, 16)
+		if _, err := rand.Read(b); err != nil {
+			return Document{}, err
+		}
+
+		o.namespace = fmt.Sprintf("https://spdx.org/spdxdocs/%s-%s", name, hex.EncodeToString(b))
+	}
+
+	ids := make(map[string]bool)
+
+	root := Package{
+		Name:             string(name),
+		SPDXID:           newID(ids, string(name)),
+		DownloadLocation: NoAssertion,
+		LicenseConcluded: NoAssertion,
+		LicenseDeclared:  NoAssertion,
+		CopyrightText:    NoAssertion,
+		ExternalRefs:     []ExternalRef{newPURL(versions.PURL(string(name), ""))},
+	}
+
+	doc := Document{
+		SPDXVersion:       Version,
+		DataLicense:       "CC0-1.0",
+		SPDXID:            documentID,
+		Name:              string(name),
+		DocumentNamespace: o.namespace,
+		CreationInfo: CreationInfo{
+			Created:  o.created.UTC().Format(time.RFC3339),
+			Creators: []string{"Tool: versions"},
+		},
+		Packages: []Package{root},
+		Relationships: []Relationship{
+			{
+				SPDXElementID:      documentID,
+				RelationshipType:   relationshipDescribes,
+				RelatedSPDXElement: root.SPDXID,
+			},
+		},
+	}
+
+	names := make([]versions.PackageName, 0, len(mod.DependencyRequirements))
+	for pkgName := range mod.DependencyRequirements {
+		names = append(names, pkgName)
+	}
+
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+
+	pkgIDs := make(map[string]string, len(names))
+
+	for _, pkgName := range names {
+		pkg := newPackage(ids, mod.DependencyRequirements[pkgName])
+		pkgIDs[string(pkgName)] = pkg.SPDXID
+
+		doc.Packages = append(doc.Packages, pkg)
+	}
+
+	graph, hasGraph := v.Graphs[name]
+
+	for _, pkgName := range names {
+		rel := Relationship{
+			SPDXElementID:      root.SPDXID,
+			RelationshipType:   relationshipDependsOn,
+			RelatedSPDXElement: pkgIDs[string(pkgName)],
+		}
+
+		if mod.DependencyRequirements[pkgName].IsIndirect {
+			rel.Comment = "indirect"
+
+			if hasGraph {
+				if parent, ok := requiredBy(graph, mod, names, pkgName); ok {
+					rel.SPDXElementID = pkgIDs[string(parent)]
+					rel.Comment = ""
+				}
+			}
+		}
+
+		doc.Relationships = append(doc.Relationships, rel)
+	}
+
+	return doc, nil
+}
+
+// WithCreated allows specifying the creation time of the document, it
+// defaults to the current time.
+func WithCreated(t time.Time) Option {
+	return func(o *options) {
+		o.created = t
+	}
+}
+
+// WithNamespace allows specifying the unique URI of the document, it defaults
+// to a random one.
+func WithNamespace(namespace string) Option {
+	return func(o *options) {
+		o.namespace = namespace
+	}
+}
+
+// licenses returns the declared and concluded licenses, using SPDX license
+// identifiers, of the package.
+func licenses(license versions.License) (declared, concluded string) {
+	switch license.Status {
+	case versions.LicenseStatusDetected:
+		if license.Identifier != "" {
+			return license.Identifier, license.Identifier
+		}
+	case versions.LicenseStatusNotFound:
+		return None, NoAssertion
+	}
+
+	return NoAssertion, NoAssertion
+}
+
+// newID returns a unique SPDX identifier for the package named name, ids
+// keeps track of the already used ones.
+func newID(ids map[string]bool, name string) string {
+	base := "SPDXRef-Package-" + strings.Trim(invalidIDChars.ReplaceAllString(name, "-"), "-")
+
+	id := base
+
+	for i := 2; ids[id]; i++ {
+		id = fmt.Sprintf("%s-%d", base, i)
+	}
+
+	ids[id] = true
+
+	return id
+}
+
+// newPURL returns the external reference to the package URL.
+func newPURL(purl string) ExternalRef {
+	return ExternalRef{
+		ReferenceCategory: "PACKAGE-MANAGER",
+		ReferenceType:     "purl",
+		ReferenceLocator:  purl,
+	}
+}
+
+func newPackage(ids map[string]bool, pkg versions.Package) Package {
+	declared, concluded := licenses(pkg.License)
+
+	res := Package{
+		Name:             string(pkg.Name),
+		SPDXID:           newID(ids, string(pkg.Name)),
+		VersionInfo:      pkg.BuiltVersion(),
+		DownloadLocation: NoAssertion,
+		LicenseConcluded: concluded,
+		LicenseDeclared:  declared,
+		CopyrightText:    NoAssertion,
+	}
+
+	if purl := pkg.PURL(); purl != "" { // local directory replacements are not described by a purl
+		res.ExternalRefs = []ExternalRef{newPURL(purl)}
+	}
+
+	return res
+}
+
+// requiredBy returns the requirement of the module, from names, requiring the
+// package according to the module graph, direct requirements are preferred.
+func requiredBy(graph versions.ModuleGraph, mod versions.Module, names []versions.PackageName, name versions.PackageName) (versions.PackageName, bool) {
+	for _, indirect := range []bool{false, true} {
+		for _, parent := range names {
+			if parent == name || mod.DependencyRequirements[parent].IsIndirect != indirect {
+				continue
+			}
+
+			version := module.Version{Path: string(parent), Version: graph.Selected(parent)}
+
+			for _, req := range graph.Requirements(version) {
+				if req.Path == string(name) {
+					return parent, true
+				}
+			}
+		}
+	}
+
+	return "", false
+}
+
+// RenderJSON writes the document in JSON format to w.
+func (d Document) RenderJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(d)
+}
+
+// RenderTagValue writes the document in tag-value format to w.
+func (d Document) RenderTagValue(w io.Writer) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "SPDXVersion: %s\n", d.SPDXVersion)
+	fmt.Fprintf(&b, "DataLicense: %s\n", d.DataLicense)
+	fmt.Fprintf(&b, "SPDXID: %s\n", d.SPDXID)
+	fmt.Fprintf(&b, "DocumentName: %s\n", d.Name)
+	fmt.Fprintf(&b, "DocumentNamespace: %s\n", d.DocumentNamespace)
+
+	for _, creator := range d.CreationInfo.Creators {
+		fmt.Fprintf(&b, "Creator: %s\n", creator)
+	}
+
+	fmt.Fprintf(&b, "Created: %s\n", d.CreationInfo.Created)
+
+	for _, pkg := range d.Packages {
+		b.WriteString("\n")
+		fmt.Fprintf(&b, "PackageName: %s\n", pkg.Name)
+		fmt.Fprintf(&b, "SPDXID: %s\n", pkg.SPDXID)
+
+		if pkg.VersionInfo != "" {
+			fmt.Fprintf(&b, "PackageVersion: %s\n", pkg.VersionInfo)
+		}
+
+		fmt.Fprintf(&b, "PackageDownloadLocation: %s\n", pkg.DownloadLocation)
+		fmt.Fprintf(&b, "FilesAnalyzed: %t\n", pkg.FilesAnalyzed)
+		fmt.Fprintf(&b, "PackageLicenseConcluded: %s\n", pkg.LicenseConcluded)
+		fmt.Fprintf(&b, "PackageLicenseDeclared: %s\n", pkg.LicenseDeclared)
+		fmt.Fprintf(&b, "PackageCopyrightText: %s\n", pkg.CopyrightText)
+
+		for _, ref := range pkg.ExternalRefs {
+			fmt.Fprintf(&b, "ExternalRef: %s %s %s\n", ref.ReferenceCategory, ref.ReferenceType, ref.ReferenceLocator)
+		}
+	}
+
+	if len(d.Relationships) > 0 {
+		b.WriteString("\n")
+	}
+
+	for _, rel := range d.Relationships {
+		fmt.Fprintf(&b, "Relationship: %s %s %s\n", rel.SPDXElementID, rel.RelationshipType, rel.RelatedSPDXElement)
+
+		if rel.Comment != "" {
+			fmt.Fprintf(&b, "RelationshipComment: %s\n", rel.Comment)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
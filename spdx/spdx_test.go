@@ -0,0 +1,185 @@
+package spdx_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/MarioCarrion/versions/internal/sbomtest"
+	"github.com/MarioCarrion/versions/spdx"
+)
+
+func Test_Document_RenderJSON(t *testing.T) {
+	t.Parallel()
+
+	doc, err := spdx.NewDocument(sbomtest.NewVersions(), sbomtest.Module, spdx.WithNamespace("https://example.com/module"))
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	var b strings.Builder
+
+	if err := doc.RenderJSON(&b); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	var got spdx.Document
+
+	if err := json.Unmarshal([]byte(b.String()), &got); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if !cmp.Equal(got, doc) {
+		t.Fatalf("expected values do not match: %s", cmp.Diff(got, doc))
+	}
+
+	for _, expected := range []string{`"spdxVersion": "SPDX-2.3"`, `"SPDXID": "SPDXRef-DOCUMENT"`, `"relatedSpdxElement": "SPDXRef-Package-fixture.com-module"`} {
+		if !strings.Contains(b.String(), expected) {
+			t.Fatalf("expected %s in %s", expected, b.String())
+		}
+	}
+}
+
+func Test_Document_RenderTagValue(t *testing.T) {
+	t.Parallel()
+
+	doc, err := spdx.NewDocument(sbomtest.NewVersions(), sbomtest.Module,
+		spdx.WithCreated(time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("", 3600))),
+		spdx.WithNamespace("https://example.com/module"))
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	expected := "" +
+		"SPDXVersion: SPDX-2.3\n" +
+		"DataLicense: CC0-1.0\n" +
+		"SPDXID: SPDXRef-DOCUMENT\n" +
+		"DocumentName: fixture.com/module\n" +
+		"DocumentNamespace: https://example.com/module\n" +
+		"Creator: Tool: versions\n" +
+		"Created: 2024-01-02T02:04:05Z\n" +
+		"\n" +
+		"PackageName: fixture.com/module\n" +
+		"SPDXID: SPDXRef-Package-fixture.com-module\n" +
+		"PackageDownloadLocation: NOASSERTION\n" +
+		"FilesAnalyzed: false\n" +
+		"PackageLicenseConcluded: NOASSERTION\n" +
+		"PackageLicenseDeclared: NOASSERTION\n" +
+		"PackageCopyrightText: NOASSERTION\n" +
+		"ExternalRef: PACKAGE-MANAGER purl pkg:golang/fixture.com/module\n" +
+		"\n" +
+		"PackageName: github.com/Example/incompatible\n" +
+		"SPDXID: SPDXRef-Package-github.com-Example-incompatible\n" +
+		"PackageVersion: v2.0.0+incompatible\n" +
+		"PackageDownloadLocation: NOASSERTION\n" +
+		"FilesAnalyzed: false\n" +
+		"PackageLicenseConcluded: MIT\n" +
+		"PackageLicenseDeclared: MIT\n" +
+		"PackageCopyrightText: NOASSERTION\n" +
+		"ExternalRef: PACKAGE-MANAGER purl pkg:golang/github.com/Example/incompatible@v2.0.0%2Bincompatible\n" +
+		"\n" +
+		"PackageName: github.com/example/indirect\n" +
+		"SPDXID: SPDXRef-Package-github.com-example-indirect\n" +
+		"PackageVersion: v1.1.0\n" +
+		"PackageDownloadLocation: NOASSERTION\n" +
+		"FilesAnalyzed: false\n" +
+		"PackageLicenseConcluded: NOASSERTION\n" +
+		"PackageLicenseDeclared: NONE\n" +
+		"PackageCopyrightText: NOASSERTION\n" +
+		"ExternalRef: PACKAGE-MANAGER purl pkg:golang/github.com/example/indirect@v1.1.0\n" +
+		"\n" +
+		"PackageName: github.com/example/local\n" +
+		"SPDXID: SPDXRef-Package-github.com-example-local\n" +
+		"PackageDownloadLocation: NOASSERTION\n" +
+		"FilesAnalyzed: false\n" +
+		"PackageLicenseConcluded: NOASSERTION\n" +
+		"PackageLicenseDeclared: NOASSERTION\n" +
+		"PackageCopyrightText: NOASSERTION\n" +
+		"\n" +
+		"PackageName: github.com/example/replaced\n" +
+		"SPDXID: SPDXRef-Package-github.com-example-replaced\n" +
+		"PackageVersion: v1.5.0\n" +
+		"PackageDownloadLocation: NOASSERTION\n" +
+		"FilesAnalyzed: false\n" +
+		"PackageLicenseConcluded: NOASSERTION\n" +
+		"PackageLicenseDeclared: NOASSERTION\n" +
+		"PackageCopyrightText: NOASSERTION\n" +
+		"ExternalRef: PACKAGE-MANAGER purl pkg:golang/github.com/fork/replaced@v1.5.0\n" +
+		"\n" +
+		"Relationship: SPDXRef-DOCUMENT DESCRIBES SPDXRef-Package-fixture.com-module\n" +
+		"Relationship: SPDXRef-Package-fixture.com-module DEPENDS_ON SPDXRef-Package-github.com-Example-incompatible\n" +
+		"Relationship: SPDXRef-Package-fixture.com-module DEPENDS_ON SPDXRef-Package-github.com-example-indirect\n" +
+		"RelationshipComment: indirect\n" +
+		"Relationship: SPDXRef-Package-fixture.com-module DEPENDS_ON SPDXRef-Package-github.com-example-local\n" +
+		"Relationship: SPDXRef-Package-fixture.com-module DEPENDS_ON SPDXRef-Package-github.com-example-replaced\n"
+
+	var b strings.Builder
+
+	if err := doc.RenderTagValue(&b); err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if got := b.String(); got != expected {
+		t.Fatalf("expected values do not match: %s", cmp.Diff(got, expected))
+	}
+}
+
+func Test_NewDocument(t *testing.T) {
+	t.Parallel()
+
+	if _, err := spdx.NewDocument(sbomtest.NewVersions(), "fixture.com/unknown"); err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+
+	first, err := spdx.NewDocument(sbomtest.NewVersions(), sbomtest.Module)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	second, err := spdx.NewDocument(sbomtest.NewVersions(), sbomtest.Module)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if !strings.HasPrefix(first.DocumentNamespace, "https://spdx.org/spdxdocs/fixture.com/module-") || first.DocumentNamespace == second.DocumentNamespace {
+		t.Fatalf("expected unique namespaces, got %s and %s", first.DocumentNamespace, second.DocumentNamespace)
+	}
+}
+
+func Test_NewDocument_ModuleGraph(t *testing.T) {
+	doc, err := spdx.NewDocument(sbomtest.NewModuleGraph(t), sbomtest.ModuleGraph)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	expected := []spdx.Relationship{
+		{
+			SPDXElementID:      "SPDXRef-DOCUMENT",
+			RelationshipType:   "DESCRIBES",
+			RelatedSPDXElement: "SPDXRef-Package-fixture.com-sbom",
+		},
+		{
+			SPDXElementID:      "SPDXRef-Package-fixture.com-sbom",
+			RelationshipType:   "DEPENDS_ON",
+			RelatedSPDXElement: "SPDXRef-Package-example.com-a",
+		},
+		{
+			SPDXElementID:      "SPDXRef-Package-example.com-a",
+			RelationshipType:   "DEPENDS_ON",
+			RelatedSPDXElement: "SPDXRef-Package-example.com-c",
+		},
+		{
+			SPDXElementID:      "SPDXRef-Package-fixture.com-sbom",
+			RelationshipType:   "DEPENDS_ON",
+			RelatedSPDXElement: "SPDXRef-Package-example.com-d",
+			Comment:            "indirect",
+		},
+	}
+
+	if !cmp.Equal(doc.Relationships, expected) {
+		t.Fatalf("expected values do not match: %s", cmp.Diff(doc.Relationships, expected))
+	}
+}
@@ -1,15 +1,582 @@
 package versions
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"go/build"
+	"io/ioutil"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/senseyeio/diligent"
+	"golang.org/x/mod/module"
+
+	"github.com/MarioCarrion/versions/goproxy"
+	"github.com/MarioCarrion/versions/osv"
 )
 
+func Test_ModuleGraph_BuildList(t *testing.T) {
+	t.Parallel()
+
+	modCache := filepath.Join("fixtures", "modcache")
+
+	tests := []struct {
+		name     string
+		input    string
+		expected []module.Version
+		goList   bool
+	}{
+		{
+			"OK",
+			"fixtures/graph/go.mod",
+			[]module.Version{
+				{Path: "example.com/a", Version: "v1.0.0"},
+				{Path: "example.com/b", Version: "v1.1.0"},
+				{Path: "example.com/c", Version: "v1.2.0"},
+				{Path: "example.com/d", Version: "v1.0.0"},
+				{Path: "example.com/e", Version: "v1.0.0"},
+			},
+			false,
+		},
+		{
+			"OK: exclude",
+			"fixtures/graph/exclude/go.mod",
+			[]module.Version{
+				{Path: "example.com/a", Version: "v1.0.0"},
+				{Path: "example.com/b", Version: "v1.1.0"},
+				{Path: "example.com/c", Version: "v1.0.0"},
+			},
+			false,
+		},
+		{
+			"OK: pruned",
+			"fixtures/graph/pruned/go.mod",
+			[]module.Version{
+				{Path: "example.com/p", Version: "v1.0.0"},
+				{Path: "example.com/q", Version: "v1.0.0"},
+				{Path: "example.com/s", Version: "v1.2.0"},
+				{Path: "example.com/u", Version: "v1.0.0"},
+				{Path: "example.com/w", Version: "v1.0.0"},
+				{Path: "example.com/x", Version: "v1.0.0"},
+			},
+			true,
+		},
+		{
+			"OK: pruned, tidy",
+			"fixtures/graph/pruned/tidy/go.mod",
+			[]module.Version{
+				{Path: "example.com/s", Version: "v1.2.0"},
+				{Path: "example.com/u", Version: "v1.0.0"},
+				{Path: "example.com/w", Version: "v1.0.0"},
+				{Path: "example.com/x", Version: "v1.0.0"},
+			},
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			modfiles, _, err := newModFiles([]string{test.input})
+			if err != nil {
+				t.Fatalf("parsing modfile %s", err)
+			}
+
+			graph, err := newModuleGraph(modCache, modfiles[0])
+			if err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+
+			if got := graph.BuildList(); !cmp.Equal(got, test.expected) {
+				t.Fatalf("expected values do not match: %s", cmp.Diff(got, test.expected))
+			}
+
+			for _, mod := range test.expected {
+				if got := graph.Selected(PackageName(mod.Path)); got != mod.Version {
+					t.Fatalf("expected %s, got %s", mod.Version, got)
+				}
+			}
+
+			if !test.goList {
+				return
+			}
+
+			if got := goListModules(t, test.input); !cmp.Equal(got, test.expected) {
+				t.Fatalf("expected values do not match go list: %s", cmp.Diff(got, test.expected))
+			}
+		})
+	}
+}
+
+func Test_New_WithDownload(t *testing.T) {
+	t.Parallel()
+
+	dir, err := filepath.Abs(filepath.Join("fixtures", "goproxy"))
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	client, err := goproxy.NewClient("file://" + filepath.ToSlash(dir))
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	modCache := t.TempDir()
+
+	withModCache := func(o *options) {
+		o.modCache = modCache
+	}
+
+	got, err := New([]string{"fixtures/download/valid/go.mod"}, WithDownload(client, ""), withModCache)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	license := got.Modules["fixture.com/download/valid"].DependencyRequirements["example.com/licensed"].License
+	if license.Status != LicenseStatusDetected || license.Identifier != "MIT" {
+		t.Fatalf("expected MIT license detected, got %+v", license)
+	}
+
+	if _, err := New([]string{"fixtures/download/mismatch/go.mod"}, WithDownload(client, ""), withModCache); err != nil {
+		t.Fatalf("expected no error for already downloaded module, got %s", err)
+	}
+
+	off, err := goproxy.NewClient("off")
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	got, err = New([]string{"fixtures/download/nosum/go.mod"}, WithDownload(client, ""), func(o *options) {
+		o.modCache = t.TempDir()
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	license = got.Modules["fixture.com/download/nosum"].DependencyRequirements["example.com/licensed"].License
+	if license.Status != LicenseStatusError || !strings.Contains(license.Error, "missing go.sum entry") {
+		t.Fatalf("expected license error, got %+v", license)
+	}
+
+	got, err = New([]string{"fixtures/download/valid/go.mod"}, WithDownload(off, ""), func(o *options) {
+		o.modCache = t.TempDir()
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	license = got.Modules["fixture.com/download/valid"].DependencyRequirements["example.com/licensed"].License
+	if license.Status != LicenseStatusNotDownloaded {
+		t.Fatalf("expected license not downloaded, got %+v", license)
+	}
+}
+
+func Test_New_WithModuleGraph(t *testing.T) {
+	t.Parallel()
+
+	withModCache := func(o *options) {
+		o.modCache = filepath.Join("fixtures", "modcache")
+	}
+
+	got, err := New([]string{"fixtures/graph/go.mod", "fixtures/graph/exclude/go.mod"}, WithModuleGraph(), withModCache)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	expected := map[ModuleName]Package{
+		"fixture.com/graph": {
+			Name:            "example.com/b",
+			Version:         "v1.1.0",
+			SelectedVersion: "v1.1.0",
+			ReplacedPath:    "./local/b",
+			License:         License{Status: LicenseStatusNotDownloaded},
+		},
+		"fixture.com/graph/exclude": {
+			Name:            "example.com/b",
+			Version:         "v1.1.0",
+			SelectedVersion: "v1.1.0",
+			License:         License{Status: LicenseStatusNotDownloaded},
+		},
+	}
+
+	if values := got.Packages.Values("example.com/b"); !cmp.Equal(values, expected) {
+		t.Fatalf("expected values do not match: %s", cmp.Diff(values, expected))
+	}
+
+	if !got.Packages.IsSame("example.com/a") {
+		t.Fatalf("expected same versions")
+	}
+
+	got, err = New([]string{"fixtures/graph/pruned/go.mod", "fixtures/graph/pruned/tidy/go.mod"}, WithModuleGraph(), withModCache)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	pruned := got.Packages.Values("example.com/s")["fixture.com/graph/pruned"]
+	if pruned.Version != "v1.0.0" || pruned.SelectedVersion != "v1.2.0" {
+		t.Fatalf("expected v1.0.0 selecting v1.2.0, got %+v", pruned)
+	}
+
+	if !got.Packages.IsSame("example.com/s") {
+		t.Fatalf("expected same selected versions")
+	}
+
+	if drift := got.Packages.Drift("example.com/s"); drift != DriftNone {
+		t.Fatalf("expected no drift, got %s", drift)
+	}
+
+	if highest := got.Packages.Highest("example.com/s"); highest != "v1.2.0" {
+		t.Fatalf("expected v1.2.0, got %s", highest)
+	}
+}
+
+func Test_New_WithVulnerabilities(t *testing.T) {
+	t.Parallel()
+
+	client, err := osv.NewClient(filepath.Join("fixtures", "osv"))
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	withModCache := func(o *options) {
+		o.modCache = filepath.Join("fixtures", "modcache")
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			"OK: upgraded past the fixed version",
+			"fixtures/graph/vulnerable/upgraded/go.mod",
+			[]string{"GO-2022-0002"},
+		},
+		{
+			"OK: downgraded to an affected version",
+			"fixtures/graph/vulnerable/downgraded/go.mod",
+			[]string{"GO-2021-0001", "GO-2022-0002"},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := New([]string{test.input}, WithoutLicenses(), WithModuleGraph(), WithVulnerabilities(client), withModCache)
+			if err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+
+			var ids []string
+
+			for _, mod := range got.Modules {
+				for _, vuln := range mod.DependencyRequirements["github.com/example/vulnerable"].Vulnerabilities {
+					ids = append(ids, vuln.ID)
+				}
+			}
+
+			if !cmp.Equal(ids, test.expected) {
+				t.Fatalf("expected values do not match: %s", cmp.Diff(ids, test.expected))
+			}
+		})
+	}
+}
+
+func Test_Versions_Why(t *testing.T) {
+	t.Parallel()
+
+	withModCache := func(o *options) {
+		o.modCache = filepath.Join("fixtures", "modcache")
+	}
+
+	got, err := New([]string{"fixtures/graph/go.mod", "fixtures/new_module_simple.mod"}, WithModuleGraph(), withModCache)
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	expected := map[ModuleName][]module.Version{
+		"fixture.com/graph": {
+			{Path: "fixture.com/graph"},
+			{Path: "example.com/b", Version: "v1.1.0"},
+			{Path: "example.com/c", Version: "v1.2.0"},
+			{Path: "example.com/e", Version: "v1.0.0"},
+		},
+	}
+
+	if why := got.Why("example.com/e"); !cmp.Equal(why, expected) {
+		t.Fatalf("expected values do not match: %s", cmp.Diff(why, expected))
+	}
+}
+
+func Test_downloader_Download(t *testing.T) {
+	t.Parallel()
+
+	dir, err := filepath.Abs(filepath.Join("fixtures", "goproxy"))
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	client, err := goproxy.NewClient("file://" + filepath.ToSlash(dir))
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	type (
+		input struct {
+			goSum   string
+			noSumDB string
+			version string
+		}
+
+		expected struct {
+			downloaded bool
+			err        error
+		}
+	)
+
+	tests := []struct {
+		name     string
+		input    input
+		expected expected
+	}{
+		{
+			"OK",
+			input{
+				goSum:   "fixtures/download/valid/go.sum",
+				version: "v1.0.0",
+			},
+			expected{
+				downloaded: true,
+			},
+		},
+		{
+			"OK: GONOSUMDB",
+			input{
+				goSum:   "fixtures/download/nosum/go.sum",
+				noSumDB: "example.com",
+				version: "v1.0.0",
+			},
+			expected{
+				downloaded: true,
+			},
+		},
+		{
+			"ERR: checksum mismatch",
+			input{
+				goSum:   "fixtures/download/mismatch/go.sum",
+				version: "v1.0.0",
+			},
+			expected{
+				err: errors.New("checksum mismatch"),
+			},
+		},
+		{
+			"ERR: missing go.sum entry",
+			input{
+				goSum:   "fixtures/download/nosum/go.sum",
+				version: "v1.0.0",
+			},
+			expected{
+				err: errors.New("missing go.sum entry"),
+			},
+		},
+		{
+			"ERR: missing go.sum entry, not downloaded",
+			input{
+				goSum:   "fixtures/download/nosum/go.sum",
+				version: "v2.0.0",
+			},
+			expected{
+				err: errMissingGoSum,
+			},
+		},
+		{
+			"ERR: not found",
+			input{
+				goSum:   "fixtures/download/nosum/go.sum",
+				noSumDB: "example.com",
+				version: "v2.0.0",
+			},
+			expected{
+				err: goproxy.ErrNotFound,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			sum, err := readGoSum(test.input.goSum)
+			if err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+
+			d := downloader{
+				client:   client,
+				noSumDB:  test.input.noSumDB,
+				modCache: t.TempDir(),
+			}
+
+			mod := module.Version{Path: "example.com/licensed", Version: test.input.version}
+
+			err = d.Download(context.Background(), mod, sum)
+			if test.expected.err == nil && err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+
+			if test.expected.err != nil && (err == nil || !errors.Is(err, test.expected.err) && !strings.Contains(err.Error(), test.expected.err.Error())) {
+				t.Fatalf("expected error %s, got %v", test.expected.err, err)
+			}
+
+			for _, file := range []string{
+				filepath.Join(d.modCache, "example.com", "licensed@"+test.input.version, "LICENSE"),
+				filepath.Join(d.modCache, "cache", "download", "example.com", "licensed", "@v", test.input.version+".zip"),
+				filepath.Join(d.modCache, "cache", "download", "example.com", "licensed", "@v", test.input.version+".ziphash"),
+				filepath.Join(d.modCache, "cache", "download", "example.com", "licensed", "@v", test.input.version+".mod"),
+			} {
+				if _, err := os.Stat(file); (err == nil) != test.expected.downloaded {
+					t.Fatalf("expected %s to exist %t, got %v", file, test.expected.downloaded, err)
+				}
+			}
+
+			if !test.expected.downloaded {
+				return
+			}
+
+			// Already downloaded modules are not downloaded again.
+			if err := d.Download(context.Background(), mod, goSum{}); err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+		})
+	}
+}
+
+func Test_excludePatterns(t *testing.T) {
+	t.Parallel()
+
+	type input struct {
+		patterns []string
+		rel      string
+		isDir    bool
+	}
+
+	tests := []struct {
+		name     string
+		input    input
+		expected bool
+	}{
+		{
+			"OK: no patterns",
+			input{
+				rel: "a/go.mod",
+			},
+			false,
+		},
+		{
+			"OK: comments and blank lines",
+			input{
+				patterns: []string{"# go.mod", "", "  "},
+				rel:      "go.mod",
+			},
+			false,
+		},
+		{
+			"OK: basename at any level",
+			input{
+				patterns: []string{"b"},
+				rel:      "a/b",
+				isDir:    true,
+			},
+			true,
+		},
+		{
+			"OK: anchored",
+			input{
+				patterns: []string{"/b"},
+				rel:      "a/b",
+				isDir:    true,
+			},
+			false,
+		},
+		{
+			"OK: directory only",
+			input{
+				patterns: []string{"go.mod/"},
+				rel:      "go.mod",
+			},
+			false,
+		},
+		{
+			"OK: glob",
+			input{
+				patterns: []string{"a/*/c"},
+				rel:      "a/b/c",
+				isDir:    true,
+			},
+			true,
+		},
+		{
+			"OK: leading double asterisk",
+			input{
+				patterns: []string{"**/c"},
+				rel:      "a/b/c",
+				isDir:    true,
+			},
+			true,
+		},
+		{
+			"OK: middle double asterisk",
+			input{
+				patterns: []string{"a/**/c"},
+				rel:      "a/c",
+				isDir:    true,
+			},
+			true,
+		},
+		{
+			"OK: trailing double asterisk",
+			input{
+				patterns: []string{"a/**"},
+				rel:      "a",
+				isDir:    true,
+			},
+			false,
+		},
+		{
+			"OK: negated",
+			input{
+				patterns: []string{"a/*", "!a/b"},
+				rel:      "a/b",
+				isDir:    true,
+			},
+			false,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			patterns := newExcludePatterns(test.input.patterns)
+			if got := patterns.Match(test.input.rel, test.input.isDir); got != test.expected {
+				t.Fatalf("expected %t, got %t", test.expected, got)
+			}
+		})
+	}
+}
+
 func Test_goModCache(t *testing.T) {
 	t.Parallel()
 
@@ -62,6 +629,114 @@ func Test_goModCache(t *testing.T) {
 	}
 }
 
+func Test_licenseCache(t *testing.T) {
+	t.Parallel()
+
+	modCache := filepath.Join("fixtures", "modcache")
+
+	license := License{
+		Identifier: "MIT",
+		Name:       "MIT License",
+		ShortName:  "MIT License",
+		Type:       diligent.OpenSource,
+		Category:   diligent.Permissive,
+		Status:     LicenseStatusDetected,
+		Confidence: 0.9,
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		status   LicenseStatus
+		disabled bool
+		expected bool
+	}{
+		{
+			"OK: module",
+			filepath.Join(modCache, "example.com", "e@v1.0.0"),
+			LicenseStatusDetected,
+			false,
+			true,
+		},
+		{
+			"OK: not found",
+			filepath.Join(modCache, "example.com", "e@v1.0.0"),
+			LicenseStatusNotFound,
+			false,
+			true,
+		},
+		{
+			"OK: disabled",
+			filepath.Join(modCache, "example.com", "e@v1.0.0"),
+			LicenseStatusDetected,
+			true,
+			false,
+		},
+		{
+			"OK: local directory",
+			filepath.Join("fixtures", "graph", "local", "b"),
+			LicenseStatusDetected,
+			false,
+			false,
+		},
+		{
+			"OK: not a module directory",
+			filepath.Join(modCache, "example.com"),
+			LicenseStatusDetected,
+			false,
+			false,
+		},
+		{
+			"OK: not downloaded",
+			filepath.Join(modCache, "example.com", "e@v1.0.0"),
+			LicenseStatusNotDownloaded,
+			false,
+			false,
+		},
+		{
+			"OK: error",
+			filepath.Join(modCache, "example.com", "e@v1.0.0"),
+			LicenseStatusError,
+			false,
+			false,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			cache := licenseCache{
+				dir:      t.TempDir(),
+				modCache: modCache,
+			}
+
+			if test.disabled {
+				cache.dir = ""
+			}
+
+			if _, ok := cache.Get(test.input); ok {
+				t.Fatalf("expected miss")
+			}
+
+			expected := license
+			expected.Status = test.status
+
+			cache.Put(test.input, expected)
+
+			actual, ok := cache.Get(test.input)
+			if ok != test.expected {
+				t.Fatalf("expected cached %t, actual %t", test.expected, ok)
+			}
+
+			if ok && !cmp.Equal(actual, expected) {
+				t.Fatalf("expected values do not match: %s", cmp.Diff(actual, expected))
+			}
+		})
+	}
+}
+
 func Test_newLicense(t *testing.T) {
 	t.Parallel()
 
@@ -79,17 +754,40 @@ func Test_newLicense(t *testing.T) {
 				ShortName:  "MIT License",
 				Type:       diligent.OpenSource,
 				Category:   diligent.Permissive,
+				Status:     LicenseStatusDetected,
+				Confidence: 0.94578314,
 			},
 		},
 		{
 			"OK: invalid",
 			"fixtures/license/invalid/",
-			License{},
+			License{
+				Status: LicenseStatusNotFound,
+			},
 		},
 		{
 			"OK: not in diligent",
 			"fixtures/license/unknown/",
-			License{},
+			License{
+				Identifier: "Beerware",
+				Status:     LicenseStatusUnrecognized,
+				Confidence: 1,
+			},
+		},
+		{
+			"OK: not downloaded",
+			"fixtures/license/does_not_exist/",
+			License{
+				Status: LicenseStatusNotDownloaded,
+			},
+		},
+		{
+			"OK: not a directory",
+			"fixtures/license/valid/LICENSE",
+			License{
+				Status: LicenseStatusError,
+				Error:  "not a directory",
+			},
 		},
 	}
 
@@ -105,10 +803,103 @@ func Test_newLicense(t *testing.T) {
 	}
 }
 
+func Test_newLicenses(t *testing.T) {
+	t.Parallel()
+
+	paths := []string{
+		"fixtures/license/invalid/",
+		"fixtures/license/unknown/",
+		"fixtures/license/valid/",
+	}
+
+	expected := map[string]License{
+		"fixtures/license/invalid/": {
+			Status: LicenseStatusNotFound,
+		},
+		"fixtures/license/unknown/": {
+			Identifier: "Beerware",
+			Status:     LicenseStatusUnrecognized,
+			Confidence: 1,
+		},
+		"fixtures/license/valid/": {
+			Identifier: "MIT",
+			Name:       "MIT License",
+			ShortName:  "MIT License",
+			Type:       diligent.OpenSource,
+			Category:   diligent.Permissive,
+			Status:     LicenseStatusDetected,
+			Confidence: 0.94578314,
+		},
+	}
+
+	for _, concurrency := range []int{0, 1, 2, 8} {
+		concurrency := concurrency
+		t.Run(fmt.Sprintf("OK: %d", concurrency), func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := newLicenses(context.Background(), licenseCache{}, paths, concurrency)
+			if err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+
+			if !cmp.Equal(actual, expected) {
+				t.Fatalf("expected values do not match: %s", cmp.Diff(actual, expected))
+			}
+		})
+	}
+
+	t.Run("OK: cached", func(t *testing.T) {
+		t.Parallel()
+
+		cache := licenseCache{
+			dir:      t.TempDir(),
+			modCache: filepath.Join("fixtures", "license", "modcache"),
+		}
+
+		var (
+			cached   = filepath.Join(cache.modCache, "example.com", "cached@v1.0.0")
+			detected = filepath.Join(cache.modCache, "example.com", "mit@v1.0.0")
+			missing  = filepath.Join(cache.modCache, "example.com", "missing@v1.0.0")
+		)
+
+		// The directory does not exist, therefore the license is the cached one.
+		cache.Put(cached, expected["fixtures/license/valid/"])
+
+		actual, err := newLicenses(context.Background(), cache, []string{cached, detected, missing}, 2)
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		if !cmp.Equal(actual[cached], expected["fixtures/license/valid/"]) {
+			t.Fatalf("expected values do not match: %s", cmp.Diff(actual[cached], expected["fixtures/license/valid/"]))
+		}
+
+		if _, ok := cache.Get(detected); !ok {
+			t.Fatalf("expected detected license to be cached")
+		}
+
+		if _, ok := cache.Get(missing); ok {
+			t.Fatalf("expected not downloaded license to not be cached")
+		}
+	})
+
+	t.Run("ERR: canceled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		if _, err := newLicenses(ctx, licenseCache{}, paths, 1); !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+	})
+}
+
 func Test_newModFiles(t *testing.T) {
 	type expected struct {
 		withErr     bool
 		moduleNames []string
+		workspaces  []Workspace
 	}
 
 	tests := []struct {
@@ -136,6 +927,40 @@ func Test_newModFiles(t *testing.T) {
 				},
 			},
 		},
+		{
+			"Workspace",
+			[]string{
+				"./fixtures/valid.mod",
+				"./fixtures/workspace/go.work",
+			},
+			expected{
+				moduleNames: []string{
+					"fixture.com/valid",
+					"fixture.com/workspace/one",
+					"fixture.com/workspace/two",
+				},
+				workspaces: []Workspace{
+					{
+						Path:      "./fixtures/workspace/go.work",
+						GoVersion: "1.21.3",
+						Toolchain: "go1.21.5",
+						Modules: []ModuleName{
+							"fixture.com/workspace/one",
+							"fixture.com/workspace/two",
+						},
+					},
+				},
+			},
+		},
+		{
+			"Invalid: workspace member not found",
+			[]string{
+				"./fixtures/workspace/invalid.work",
+			},
+			expected{
+				withErr: true,
+			},
+		},
 		{
 			"Invalid: not found",
 			[]string{
@@ -159,7 +984,7 @@ func Test_newModFiles(t *testing.T) {
 	for _, test := range tests {
 		test := test
 		t.Run(test.name, func(t *testing.T) {
-			modfiles, err := newModFiles(test.input)
+			modfiles, workspaces, err := newModFiles(test.input)
 
 			if test.expected.withErr == (err == nil) {
 				t.Fatalf("expected error: %t, got %s", test.expected.withErr, err)
@@ -177,6 +1002,10 @@ func Test_newModFiles(t *testing.T) {
 			if !cmp.Equal(actual, test.expected.moduleNames) {
 				t.Fatalf("expected values do not match: %s", cmp.Diff(actual, test.expected.moduleNames))
 			}
+
+			if !cmp.Equal(workspaces, test.expected.workspaces) {
+				t.Fatalf("expected values do not match: %s", cmp.Diff(workspaces, test.expected.workspaces))
+			}
 		})
 	}
 }
@@ -195,6 +1024,7 @@ func Test_newModule(t *testing.T) {
 					Name:      "fixture.com/new_module_simple",
 					GoVersion: "1.13",
 				},
+				File: "fixtures/new_module_simple.mod",
 				DependencyRequirements: map[PackageName]Package{
 					"github.com/MarioCarrion/nit": {
 						Name:    "github.com/MarioCarrion/nit",
@@ -215,6 +1045,7 @@ func Test_newModule(t *testing.T) {
 					Name:      "fixture.com/new_module_replace",
 					GoVersion: "1.14",
 				},
+				File: "fixtures/new_module_replace.mod",
 				DependencyRequirements: map[PackageName]Package{
 					"github.com/MarioCarrion/nit": {
 						Name:            "github.com/MarioCarrion/nit",
@@ -233,6 +1064,7 @@ func Test_newModule(t *testing.T) {
 					Name:      "fixture.com/new_module_replace_2",
 					GoVersion: "1.14",
 				},
+				File:                   "fixtures/new_module_replace_2.mod",
 				DependencyRequirements: map[PackageName]Package{},
 			},
 		},
@@ -244,6 +1076,7 @@ func Test_newModule(t *testing.T) {
 					Name:      "fixture.com/new_module_indirect",
 					GoVersion: "1.14",
 				},
+				File: "fixtures/new_module_indirect.mod",
 				DependencyRequirements: map[PackageName]Package{
 					"github.com/MarioCarrion/indirect": {
 						Name:       "github.com/MarioCarrion/indirect",
@@ -260,7 +1093,7 @@ func Test_newModule(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			t.Parallel()
 
-			modfile, err := newModFiles([]string{test.input})
+			modfile, _, err := newModFiles([]string{test.input})
 			if err != nil {
 				t.Fatalf("parsing modfile %s", err)
 			}
@@ -271,3 +1104,299 @@ func Test_newModule(t *testing.T) {
 		})
 	}
 }
+
+func Test_newModuleGraph(t *testing.T) {
+	t.Parallel()
+
+	modCache := filepath.Join("fixtures", "modcache")
+
+	type expected struct {
+		modules      []module.Version
+		requirements map[module.Version][]module.Version
+		missing      []module.Version
+		why          map[PackageName][]module.Version
+		err          bool
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected expected
+	}{
+		{
+			"OK",
+			"fixtures/graph/go.mod",
+			expected{
+				modules: []module.Version{
+					{Path: "example.com/a", Version: "v1.0.0"},
+					{Path: "example.com/b", Version: "v1.1.0"},
+					{Path: "example.com/c", Version: "v1.0.0"},
+					{Path: "example.com/c", Version: "v1.2.0"},
+					{Path: "example.com/d", Version: "v1.0.0"},
+					{Path: "example.com/e", Version: "v1.0.0"},
+				},
+				requirements: map[module.Version][]module.Version{
+					{Path: "fixture.com/graph"}: {
+						{Path: "example.com/a", Version: "v1.0.0"},
+						{Path: "example.com/b", Version: "v1.1.0"},
+					},
+					{Path: "example.com/b", Version: "v1.1.0"}: {
+						{Path: "example.com/c", Version: "v1.2.0"},
+						{Path: "example.com/d", Version: "v1.0.0"},
+					},
+					{Path: "example.com/e", Version: "v1.0.0"}: {},
+				},
+				missing: []module.Version{
+					{Path: "example.com/d", Version: "v1.0.0"},
+				},
+				why: map[PackageName][]module.Version{
+					"example.com/e": {
+						{Path: "fixture.com/graph"},
+						{Path: "example.com/b", Version: "v1.1.0"},
+						{Path: "example.com/c", Version: "v1.2.0"},
+						{Path: "example.com/e", Version: "v1.0.0"},
+					},
+					"example.com/c": {
+						{Path: "fixture.com/graph"},
+						{Path: "example.com/a", Version: "v1.0.0"},
+						{Path: "example.com/c", Version: "v1.0.0"},
+					},
+					"example.com/f": nil,
+				},
+			},
+		},
+		{
+			"OK: pruned",
+			"fixtures/graph/pruned/go.mod",
+			expected{
+				modules: []module.Version{
+					{Path: "example.com/p", Version: "v1.0.0"},
+					{Path: "example.com/q", Version: "v1.0.0"},
+					{Path: "example.com/s", Version: "v1.0.0"},
+					{Path: "example.com/s", Version: "v1.2.0"},
+					{Path: "example.com/u", Version: "v1.0.0"},
+					{Path: "example.com/w", Version: "v1.0.0"},
+					{Path: "example.com/x", Version: "v1.0.0"},
+				},
+				requirements: map[module.Version][]module.Version{
+					{Path: "example.com/p", Version: "v1.0.0"}: {
+						{Path: "example.com/q", Version: "v1.0.0"},
+						{Path: "example.com/s", Version: "v1.2.0"},
+						{Path: "fixture.com/graph/pruned", Version: "v0.1.0"},
+					},
+					{Path: "example.com/q", Version: "v1.0.0"}: {},
+					{Path: "example.com/w", Version: "v1.0.0"}: {
+						{Path: "example.com/x", Version: "v1.0.0"},
+					},
+				},
+				why: map[PackageName][]module.Version{
+					"example.com/x": {
+						{Path: "fixture.com/graph/pruned"},
+						{Path: "example.com/u", Version: "v1.0.0"},
+						{Path: "example.com/w", Version: "v1.0.0"},
+						{Path: "example.com/x", Version: "v1.0.0"},
+					},
+					"example.com/r": nil,
+				},
+			},
+		},
+		{
+			"Error: checksum mismatch",
+			"fixtures/graph/checksum_mismatch/go.mod",
+			expected{
+				err: true,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			modfiles, _, err := newModFiles([]string{test.input})
+			if err != nil {
+				t.Fatalf("parsing modfile %s", err)
+			}
+
+			graph, err := newModuleGraph(modCache, modfiles[0])
+			if (err != nil) != test.expected.err {
+				t.Fatalf("expected error %t, got %s", test.expected.err, err)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if got := graph.Modules(); !cmp.Equal(got, test.expected.modules) {
+				t.Fatalf("expected values do not match: %s", cmp.Diff(got, test.expected.modules))
+			}
+
+			for mod, expected := range test.expected.requirements {
+				if got := graph.Requirements(mod); !cmp.Equal(got, expected) {
+					t.Fatalf("expected values do not match: %s", cmp.Diff(got, expected))
+				}
+			}
+
+			for _, mod := range test.expected.missing {
+				if !graph.IsMissing(mod) {
+					t.Fatalf("expected %s to be missing", mod)
+				}
+			}
+
+			for name, expected := range test.expected.why {
+				if got := graph.Why(name); !cmp.Equal(got, expected) {
+					t.Fatalf("expected values do not match: %s", cmp.Diff(got, expected))
+				}
+			}
+		})
+	}
+}
+
+func Test_newUpdates(t *testing.T) {
+	t.Parallel()
+
+	dir, err := filepath.Abs(filepath.Join("fixtures", "goproxy"))
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	client, err := goproxy.NewClient("file://" + filepath.ToSlash(dir))
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	type input struct {
+		path    string
+		version string
+	}
+
+	tests := []struct {
+		name     string
+		input    input
+		expected Updates
+	}{
+		{
+			"OK: patch, minor and major",
+			input{
+				path:    "example.com/pkg",
+				version: "v1.0.0",
+			},
+			Updates{
+				Patch: "v1.0.1",
+				Minor: "v1.1.0",
+				Major: "v2.1.0",
+			},
+		},
+		{
+			"OK: major version path",
+			input{
+				path:    "example.com/pkg/v2",
+				version: "v2.0.0",
+			},
+			Updates{
+				Minor: "v2.1.0",
+			},
+		},
+		{
+			"OK: pseudo-version",
+			input{
+				path:    "example.com/pseudo",
+				version: "v0.0.0-20200101000000-abcdefabcdef",
+			},
+			Updates{
+				Patch: "v0.0.0-20200102000000-abcdefabcdef",
+			},
+		},
+		{
+			"OK: latest",
+			input{
+				path:    "example.com/other",
+				version: "v0.1.0",
+			},
+			Updates{},
+		},
+		{
+			"OK: not found",
+			input{
+				path:    "example.com/does_not_exist",
+				version: "v0.1.0",
+			},
+			Updates{},
+		},
+		{
+			"OK: invalid version",
+			input{
+				path:    "example.com/pkg",
+				version: "latest",
+			},
+			Updates{},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := newUpdates(context.Background(), client, test.input.path, test.input.version)
+			if err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+
+			if !cmp.Equal(got, test.expected) {
+				t.Fatalf("expected values do not match: %s", cmp.Diff(got, test.expected))
+			}
+		})
+	}
+}
+
+// goListModules returns the modules listed by "go list -m all", excluding the
+// main one, using a copy of the go.mod and go.sum files and the module cache
+// fixture as an offline proxy.
+func goListModules(t *testing.T, file string) []module.Version {
+	t.Helper()
+
+	proxy, err := filepath.Abs(filepath.Join("fixtures", "modcache", "cache", "download"))
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	dir := t.TempDir()
+
+	for _, name := range []string{"go.mod", "go.sum"} {
+		data, err := ioutil.ReadFile(filepath.Join(filepath.Dir(file), name))
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		if err := ioutil.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+	}
+
+	cmd := exec.Command("go", "list", "-m", "all")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GOFLAGS=-mod=mod -modcacherw",
+		"GOMODCACHE="+filepath.Join(dir, "modcache"),
+		"GOPROXY=file://"+filepath.ToSlash(proxy),
+		"GOSUMDB=off",
+		"GOTOOLCHAIN=local",
+		"GOWORK=off",
+	)
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("go list: %s: %s", err, out)
+	}
+
+	var res []module.Version
+
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n")[1:] {
+		fields := strings.Fields(line)
+		res = append(res, module.Version{Path: fields[0], Version: fields[1]})
+	}
+
+	return res
+}
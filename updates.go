@@ -0,0 +1,117 @@
+package versions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+
+	"github.com/MarioCarrion/versions/goproxy"
+)
+
+// listReleases returns the released versions of the module, when there are
+// no released versions, the latest known one is returned instead.
+func listReleases(ctx context.Context, client *goproxy.Client, path string) ([]string, error) {
+	list, err := client.List(ctx, path)
+	if err != nil {
+		if errors.Is(err, goproxy.ErrNotFound) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var res []string
+
+	for _, v := range list {
+		if semver.Prerelease(v) == "" {
+			res = append(res, v)
+		}
+	}
+
+	if len(res) > 0 {
+		return res, nil
+	}
+
+	info, err := client.Latest(ctx, path)
+	if err != nil {
+		if errors.Is(err, goproxy.ErrNotFound) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	if !semver.IsValid(info.Version) {
+		return nil, nil
+	}
+
+	return []string{info.Version}, nil
+}
+
+func newUpdates(ctx context.Context, client *goproxy.Client, path, version string) (Updates, error) {
+	var res Updates
+
+	if !semver.IsValid(version) {
+		return res, nil
+	}
+
+	list, err := listReleases(ctx, client, path)
+	if err != nil {
+		return Updates{}, err
+	}
+
+	setMax := func(current *string, v string) {
+		if *current == "" || semver.Compare(v, *current) > 0 {
+			*current = v
+		}
+	}
+
+	for _, v := range list {
+		if semver.Compare(v, version) <= 0 {
+			continue
+		}
+
+		switch {
+		case semver.MajorMinor(v) == semver.MajorMinor(version):
+			setMax(&res.Patch, v)
+		case semver.Major(v) == semver.Major(version):
+			setMax(&res.Minor, v)
+		default: // "+incompatible" versions
+			setMax(&res.Major, v)
+		}
+	}
+
+	prefix, pathMajor, ok := module.SplitPathVersion(path)
+	if !ok || strings.HasPrefix(pathMajor, ".") { // gopkg.in paths are not supported
+		return res, nil
+	}
+
+	major := 1
+	if pathMajor != "" {
+		if major, err = strconv.Atoi(strings.TrimPrefix(pathMajor, "/v")); err != nil {
+			return res, nil
+		}
+	}
+
+	for {
+		major++
+
+		list, err := listReleases(ctx, client, fmt.Sprintf("%s/v%d", prefix, major))
+		if err != nil {
+			return Updates{}, err
+		}
+
+		if len(list) == 0 {
+			break
+		}
+
+		setMax(&res.Major, list[len(list)-1])
+	}
+
+	return res, nil
+}
@@ -1,23 +1,33 @@
 package versions
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"go/build"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
 
-	"github.com/go-enry/go-license-detector/v4/licensedb"
-	"github.com/go-enry/go-license-detector/v4/licensedb/api"
-	"github.com/go-enry/go-license-detector/v4/licensedb/filer"
 	"github.com/senseyeio/diligent"
 	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+
+	"github.com/MarioCarrion/versions/goproxy"
+	"github.com/MarioCarrion/versions/osv"
 )
 
 type (
 	// GoVersion defines the module version of Go used by the Module.
 	GoVersion string
 
+	// LicenseStatus indicates the result of detecting the License of a
+	// Package.
+	LicenseStatus string
+
 	// ModuleName defines the name of the module.
 	ModuleName string
 
@@ -26,23 +36,52 @@ type (
 
 	//-
 
-	// License represents the LICENSE used by a Package.
+	// License represents the LICENSE used by a Package, Status indicates
+	// whether it was detected and Confidence is the score, between 0 and 1,
+	// of the best match found by the detector. Error is only set when Status
+	// is LicenseStatusError.
 	License struct {
 		Identifier string
 		Name       string
 		ShortName  string
 		Type       diligent.Type
 		Category   diligent.Category
+		Status     LicenseStatus
+		Error      string
+		Confidence float32
 	}
 
-	// Package represents an imported Go packaged in a Module.
+	// Package represents an imported Go packaged in a Module, Version is the
+	// one declared in go.mod and SelectedVersion the one selected by Minimal
+	// Version Selection, only known when the module graph is built.
 	Package struct {
 		Name            PackageName
 		Version         string
+		SelectedVersion string
 		IsIndirect      bool
 		ReplacedPath    string
 		ReplacedVersion string
 		License         License
+		Updates         Updates
+		Vulnerabilities []Vulnerability
+	}
+
+	// Updates represents the newest versions available for a Package, empty
+	// values indicate there are no newer versions.
+	Updates struct {
+		Patch string
+		Minor string
+		Major string
+	}
+
+	// Vulnerability represents a known vulnerability affecting a Package,
+	// Fixed is the lowest version fixing it, empty when there is no fix.
+	Vulnerability struct {
+		ID       string
+		Aliases  []string
+		Summary  string
+		Severity string
+		Fixed    string
 	}
 
 	//-
@@ -73,9 +112,10 @@ type (
 
 	//-
 
-	// Module represents the contents of a go.mod file.
+	// Module represents the contents of a go.mod file, File is its path.
 	Module struct {
 		ModuleGoVersion
+		File                   string
 		DependencyRequirements map[PackageName]Package
 	}
 
@@ -84,36 +124,227 @@ type (
 		Modules    map[ModuleName]Module
 		GoVersions GoVersions
 		Packages   Packages
+		Workspaces []Workspace
+		Graphs     map[ModuleName]ModuleGraph
+	}
+
+	// Workspace represents the contents of a go.work file.
+	Workspace struct {
+		Path      string
+		GoVersion GoVersion
+		Toolchain string
+		Modules   []ModuleName
+	}
+
+	//-
+
+	// Option is configuration option for New.
+	Option func(*options)
+
+	//-
+
+	options struct {
+		updates     *goproxy.Client
+		graph       bool
+		modCache    string
+		concurrency int
+		cacheDir    string
+		download    *goproxy.Client
+		noSumDB     string
+		noLicenses  bool
+		vulns       *osv.Client
 	}
 )
 
-// New returns the parsed versions used by all the mod files.
-func New(files []string) (Versions, error) {
-	parsed, err := newModFiles(files)
+const (
+	// LicenseStatusDetected indicates the license was detected and it is
+	// known.
+	LicenseStatusDetected LicenseStatus = "detected"
+
+	// LicenseStatusError indicates the license could not be detected because
+	// of an error.
+	LicenseStatusError LicenseStatus = "error"
+
+	// LicenseStatusNotDownloaded indicates the package is not in the module
+	// cache.
+	LicenseStatusNotDownloaded LicenseStatus = "not-downloaded"
+
+	// LicenseStatusNotFound indicates the package does not include a license.
+	LicenseStatusNotFound LicenseStatus = "not-found"
+
+	// LicenseStatusUnrecognized indicates the license was detected but its
+	// identifier is not known, only Identifier and Confidence are set.
+	LicenseStatusUnrecognized LicenseStatus = "unrecognized"
+)
+
+// New returns the parsed versions used by all the mod files, it is equivalent
+// to NewContext using context.Background().
+func New(files []string, opts ...Option) (Versions, error) {
+	return NewContext(context.Background(), files, opts...)
+}
+
+// NewContext returns the parsed versions used by all the mod files, licenses
+// are detected concurrently and ctx cancellation stops any pending detection
+// or update lookup.
+//
+// Files with the ".work" extension are parsed as go.work files: their "use"
+// directives are expanded into the member go.mod files and their "replace"
+// directives are applied on top of the ones defined by each member. Members
+// are resolved independently: when using WithModuleGraph the versions are
+// selected per member, not across the whole workspace, and go.work.sum is
+// not used to verify go.mod files.
+func NewContext(ctx context.Context, files []string, opts ...Option) (Versions, error) {
+	options := options{
+		modCache:    goModCache(),
+		concurrency: runtime.NumCPU(),
+	}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	parsed, workspaces, err := newModFiles(files)
 	if err != nil {
 		return Versions{}, err
 	}
 
 	result := Versions{
-		Modules: make(map[ModuleName]Module),
+		Modules:    make(map[ModuleName]Module),
+		Workspaces: workspaces,
 	}
 
-	licenses := make(map[string]License)
+	var (
+		modules   = make([]Module, 0, len(parsed))
+		paths     []string
+		seen      = make(map[string]bool)
+		downloads []module.Version
+		sums      = make(goSum)
+	)
 
 	for _, modfile := range parsed {
+		if options.download != nil {
+			sum, err := readGoSum(filepath.Join(filepath.Dir(modfile.Syntax.Name), "go.sum"))
+			if err != nil {
+				return Versions{}, err
+			}
+
+			sums.Merge(sum)
+		}
+
 		module := newModule(modfile)
 
 		result.Modules[module.Name] = module
 		result.GoVersions.Set(module.Name, module.GoVersion)
 
+		if options.graph {
+			if result.Graphs == nil {
+				result.Graphs = make(map[ModuleName]ModuleGraph)
+			}
+
+			graph, err := newModuleGraph(options.modCache, modfile)
+			if err != nil {
+				return Versions{}, err
+			}
+
+			result.Graphs[module.Name] = graph
+
+			for _, selected := range graph.BuildList() {
+				pkg, ok := module.DependencyRequirements[PackageName(selected.Path)]
+				if !ok {
+					continue
+				}
+
+				pkg.SelectedVersion = selected.Version
+				module.DependencyRequirements[pkg.Name] = pkg
+			}
+		}
+
+		for _, pkg := range module.DependencyRequirements {
+			if path := pkg.path(options.modCache); !seen[path] {
+				seen[path] = true
+				paths = append(paths, path)
+
+				if mod, ok := pkg.module(); ok && mod.Version != "" {
+					downloads = append(downloads, mod)
+				}
+			}
+		}
+
+		modules = append(modules, module)
+	}
+
+	sort.Strings(paths)
+
+	var failed map[module.Version]error
+
+	if options.download != nil {
+		if failed, err = download(ctx, options, sums, downloads); err != nil {
+			return Versions{}, err
+		}
+	}
+
+	cache := licenseCache{
+		dir:      options.cacheDir,
+		modCache: options.modCache,
+	}
+
+	if options.noLicenses {
+		paths = nil
+	}
+
+	licenses, err := newLicenses(ctx, cache, paths, options.concurrency)
+	if err != nil {
+		return Versions{}, err
+	}
+
+	var (
+		updates = make(map[string]Updates)
+		vulns   = make(map[string][]Vulnerability)
+	)
+
+	for _, module := range modules {
 		for k, pkg := range module.DependencyRequirements {
-			license, ok := licenses[pkg.Path()]
-			if !ok {
-				license = newLicense(pkg.Path())
-				licenses[pkg.Path()] = license
+			pkg.License = licenses[pkg.path(options.modCache)]
+
+			if mod, ok := pkg.module(); ok && pkg.License.Status == LicenseStatusNotDownloaded {
+				if err, ok := failed[mod]; ok {
+					pkg.License = License{Status: LicenseStatusError, Error: err.Error()}
+				}
 			}
 
-			pkg.License = license
+			if options.updates != nil {
+				key := fmt.Sprintf("%s@%s", pkg.Name, pkg.Version)
+
+				update, ok := updates[key]
+				if !ok {
+					update, err = newUpdates(ctx, options.updates, string(pkg.Name), pkg.Version)
+					if errors.Is(err, goproxy.ErrDisabled) {
+						options.updates = nil
+					} else if err != nil {
+						return Versions{}, err
+					}
+
+					updates[key] = update
+				}
+
+				pkg.Updates = update
+			}
+
+			if mod, ok := pkg.module(); ok && options.vulns != nil {
+				mod.Version = pkg.BuiltVersion()
+				key := fmt.Sprintf("%s@%s", mod.Path, mod.Version)
+
+				vuln, ok := vulns[key]
+				if !ok {
+					if vuln, err = newVulnerabilities(ctx, options.vulns, mod); err != nil {
+						return Versions{}, err
+					}
+
+					vulns[key] = vuln
+				}
+
+				pkg.Vulnerabilities = vuln
+			}
 
 			module.DependencyRequirements[k] = pkg
 
@@ -124,6 +355,74 @@ func New(files []string) (Versions, error) {
 	return result, nil
 }
 
+// WithConcurrency allows specifying the maximum number of licenses detected
+// concurrently, it defaults to the number of CPUs; values lower than 1 are
+// treated as 1.
+func WithConcurrency(n int) Option {
+	return func(o *options) {
+		o.concurrency = n
+	}
+}
+
+// WithDownload allows downloading, into the module cache, the modules not
+// found there yet, using the client to query the module proxies; this is done
+// before detecting licenses. Downloaded modules are verified using go.sum,
+// modules not in go.sum are only allowed when their path matches the
+// comma-separated list of glob patterns in noSumDB, like GONOSUMDB and
+// GOPRIVATE.
+func WithDownload(client *goproxy.Client, noSumDB string) Option {
+	return func(o *options) {
+		o.download = client
+		o.noSumDB = noSumDB
+	}
+}
+
+// WithLicenseCache allows persisting the detected licenses in dir, so they
+// are not detected again; only modules in the module cache are cached, local
+// directories, like the ones used by replace directives, are always detected.
+func WithLicenseCache(dir string) Option {
+	return func(o *options) {
+		o.cacheDir = dir
+	}
+}
+
+// WithModuleGraph allows building the module requirement graph of each
+// Module, using the go.mod files of all its dependencies found in the module
+// cache.
+func WithModuleGraph() Option {
+	return func(o *options) {
+		o.graph = true
+	}
+}
+
+// WithUpdates allows determining the newest versions available for each
+// Package, using the client to query the module proxies; when the proxies are
+// disabled, because of GOPROXY=off, no updates are determined.
+func WithUpdates(client *goproxy.Client) Option {
+	return func(o *options) {
+		o.updates = client
+	}
+}
+
+// WithVulnerabilities allows determining the known vulnerabilities affecting
+// each Package, using the client to query the Go vulnerability database; the
+// version selected by Minimal Version Selection is checked when known, the
+// replacement module is the one checked for replaced packages, packages
+// replaced by local directories are not checked.
+func WithVulnerabilities(client *osv.Client) Option {
+	return func(o *options) {
+		o.vulns = client
+	}
+}
+
+// WithoutLicenses allows skipping the license detection, useful when only the
+// versions are needed.
+func WithoutLicenses() Option {
+	return func(o *options) {
+		o.noLicenses = true
+	}
+}
+
 func goModCache() string {
 	if gomodcache := os.Getenv("GOMODCACHE"); gomodcache != "" {
 		return gomodcache
@@ -137,65 +436,43 @@ func goModCache() string {
 	return filepath.Join(gopath, "pkg", "mod")
 }
 
-func newLicense(path string) License {
-	filer, err := filer.FromDirectory(path)
+func newModFile(file string) (*modfile.File, error) {
+	data, err := ioutil.ReadFile(file)
 	if err != nil {
-		return License{}
+		return nil, err
 	}
 
-	licenses, err := licensedb.Detect(filer)
-	if err != nil {
-		return License{}
-	}
+	return modfile.Parse(file, data, nil)
+}
 
+func newModFiles(files []string) ([]*modfile.File, []Workspace, error) {
 	var (
-		match api.Match
-		name  string
+		parsed     []*modfile.File
+		workspaces []Workspace
 	)
 
-	for k, v := range licenses {
-		if name == "" || v.Confidence > match.Confidence {
-			match = v
-			name = k
-		}
-	}
-
-	if name == "" {
-		return License{}
-	}
-
-	license, err := diligent.GetLicenseFromIdentifier(name)
-	if err != nil {
-		return License{}
-	}
-
-	return License{
-		Identifier: license.Identifier,
-		Name:       license.Name,
-		ShortName:  license.ShortName,
-		Type:       license.Type,
-		Category:   license.Category,
-	}
-}
+	for _, file := range files {
+		if filepath.Ext(file) != ".work" {
+			f, err := newModFile(file)
+			if err != nil {
+				return nil, nil, err
+			}
 
-func newModFiles(files []string) ([]*modfile.File, error) {
-	parsed := make([]*modfile.File, len(files))
+			parsed = append(parsed, f)
 
-	for i, file := range files {
-		data, err := ioutil.ReadFile(file)
-		if err != nil {
-			return nil, err
+			continue
 		}
 
-		f, err := modfile.Parse(file, data, nil)
+		workspace, members, err := newWorkspace(file)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
-		parsed[i] = f
+		parsed = append(parsed, members...)
+		workspaces = append(workspaces, workspace)
 	}
 
-	return parsed, nil
+	return parsed, workspaces, nil
 }
 
 func newModule(modfile *modfile.File) Module {
@@ -204,6 +481,7 @@ func newModule(modfile *modfile.File) Module {
 			Name:      ModuleName(modfile.Module.Mod.Path),
 			GoVersion: GoVersion(modfile.Go.Version),
 		},
+		File: modfile.Syntax.Name,
 	}
 
 	dependencies := make(map[PackageName]Package)
@@ -218,7 +496,7 @@ func newModule(modfile *modfile.File) Module {
 
 	for _, replace := range modfile.Replace {
 		pkg, ok := dependencies[PackageName(replace.Old.Path)]
-		if !ok {
+		if !ok || (replace.Old.Version != "" && replace.Old.Version != pkg.Version) {
 			continue
 		}
 
@@ -233,6 +511,58 @@ func newModule(modfile *modfile.File) Module {
 	return module
 }
 
+// newWorkspace returns the workspace defined in the go.work file and the
+// go.mod files of its members, each one including the workspace replacements;
+// members are resolved independently of each other.
+func newWorkspace(file string) (Workspace, []*modfile.File, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return Workspace{}, nil, err
+	}
+
+	work, err := modfile.ParseWork(file, data, nil)
+	if err != nil {
+		return Workspace{}, nil, err
+	}
+
+	workspace := Workspace{
+		Path: file,
+	}
+
+	if work.Go != nil {
+		workspace.GoVersion = GoVersion(work.Go.Version)
+	}
+
+	if work.Toolchain != nil {
+		workspace.Toolchain = work.Toolchain.Name
+	}
+
+	members := make([]*modfile.File, len(work.Use))
+
+	for i, use := range work.Use {
+		dir := use.Path
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(filepath.Dir(file), dir)
+		}
+
+		f, err := newModFile(filepath.Join(dir, "go.mod"))
+		if err != nil {
+			return Workspace{}, nil, err
+		}
+
+		for _, replace := range work.Replace {
+			if err := f.AddReplace(replace.Old.Path, replace.Old.Version, replace.New.Path, replace.New.Version); err != nil {
+				return Workspace{}, nil, err
+			}
+		}
+
+		members[i] = f
+		workspace.Modules = append(workspace.Modules, ModuleName(f.Module.Mod.Path))
+	}
+
+	return workspace, members, nil
+}
+
 // IsSame returns true when all Modules use the same Go Version.
 func (g *GoVersions) IsSame() bool {
 	return g.same
@@ -272,27 +602,134 @@ func (g *GoVersions) Values() []ModuleGoVersion {
 	return result
 }
 
+// BuiltVersion returns the version of the module being built: the replacement
+// version when replaced, empty for local directories, otherwise the effective
+// one, see EffectiveVersion.
+func (p Package) BuiltVersion() string {
+	if p.ReplacedPath != "" {
+		return p.ReplacedVersion
+	}
+
+	return p.EffectiveVersion()
+}
+
+// EffectiveVersion returns the version selected by Minimal Version Selection
+// when known, otherwise the declared one.
+func (p Package) EffectiveVersion() string {
+	if p.SelectedVersion != "" {
+		return p.SelectedVersion
+	}
+
+	return p.Version
+}
+
+// PURL returns the package URL of the module being built: the replacement
+// when replaced by a module, otherwise the package using the version selected
+// by Minimal Version Selection when known. An empty string is returned when
+// replaced by a local directory.
+func (p Package) PURL() string {
+	if p.ReplacedPath != "" {
+		if p.ReplacedVersion == "" {
+			return ""
+		}
+
+		return PURL(p.ReplacedPath, p.ReplacedVersion)
+	}
+
+	return PURL(string(p.Name), p.EffectiveVersion())
+}
+
 // Path returns the full filesystem path pointing to the package
 func (p Package) Path() string {
-	version := func(v string) string {
-		if v == "" {
-			return ""
+	return p.path(goModCache())
+}
+
+// isSameVersion returns true when both packages are built using the same
+// version, that is the same effective version and replacement; other fields,
+// like License or IsIndirect, are ignored.
+func (p Package) isSameVersion(other Package) bool {
+	return VersionDrift(p.EffectiveVersion(), other.EffectiveVersion()) == DriftNone &&
+		p.ReplacedPath == other.ReplacedPath &&
+		p.ReplacedVersion == other.ReplacedVersion
+}
+
+// module returns the module version containing the package, false is
+// returned when the package is replaced with a local directory.
+func (p Package) module() (module.Version, bool) {
+	if p.ReplacedPath == "" {
+		return module.Version{Path: string(p.Name), Version: p.Version}, true
+	}
+
+	if p.ReplacedVersion == "" {
+		return module.Version{}, false
+	}
+
+	return module.Version{Path: p.ReplacedPath, Version: p.ReplacedVersion}, true
+}
+
+// path returns the directory of the package in the module cache, paths and
+// versions are escaped the same way the go command does, as long as they are
+// valid.
+func (p Package) path(modCache string) string {
+	mod, ok := p.module()
+	if !ok {
+		return filepath.Join(modCache, p.ReplacedPath)
+	}
+
+	if mod.Version == "" {
+		return filepath.Join(modCache, mod.Path)
+	}
+
+	escapedPath, errPath := module.EscapePath(mod.Path)
+	escapedVersion, errVersion := module.EscapeVersion(mod.Version)
+
+	if errPath != nil || errVersion != nil {
+		return filepath.Join(modCache, mod.Path+"@"+mod.Version)
+	}
+
+	return filepath.Join(modCache, escapedPath+"@"+escapedVersion)
+}
+
+// Drift returns the most severe Drift between the version used by each Module
+// and the highest one, see Highest; DriftReplaced is returned when the
+// versions are the same but not the replacements, see IsSame.
+func (p *Packages) Drift(value PackageName) Drift {
+	highest := p.Highest(value)
+
+	var res Drift
+
+	for _, pkg := range p.packages[value] {
+		if drift := VersionDrift(pkg.EffectiveVersion(), highest); drift.Severity() > res.Severity() {
+			res = drift
 		}
+	}
 
-		return fmt.Sprintf("@%s", v)
+	if res == DriftNone && len(p.packages[value]) > 0 && !p.IsSame(value) {
+		res = DriftReplaced
 	}
 
-	var newVersion string
-	if p.ReplacedPath != "" {
-		newVersion = fmt.Sprintf("%s%s", p.ReplacedPath, version(p.ReplacedVersion))
-	} else {
-		newVersion = fmt.Sprintf("%s%s", p.Name, version(p.Version))
+	return res
+}
+
+// Highest returns the highest semantic version of the Package used by any
+// Module, the selected version is used instead of the declared one when
+// known. An empty string is returned when no valid version is used.
+func (p *Packages) Highest(value PackageName) string {
+	var res string
+
+	for _, pkg := range p.packages[value] {
+		if version := pkg.EffectiveVersion(); semver.IsValid(version) && (res == "" || semver.Compare(version, res) > 0) {
+			res = version
+		}
 	}
 
-	return filepath.Join(goModCache(), newVersion)
+	return res
 }
 
-// IsSame returns true when all Modules use the same Package Version.
+// IsSame returns true when all Modules use the same Package Version, the
+// selected version is used instead of the declared one when known. Versions
+// are compared semantically, and replacements must match, but other fields
+// like License or IsIndirect are ignored.
 func (p *Packages) IsSame(value PackageName) bool {
 	if p.sameVersions == nil {
 		return false
@@ -333,7 +770,7 @@ func (p *Packages) Set(name ModuleName, pkg Package) {
 
 	p.packages[pkg.Name] = mods
 
-	if p.sameVersions[pkg.Name] && pkg != p.lastVersions[pkg.Name] {
+	if p.sameVersions[pkg.Name] && !pkg.isSameVersion(p.lastVersions[pkg.Name]) {
 		p.sameVersions[pkg.Name] = false
 	}
 }
@@ -352,3 +789,67 @@ func (p *Packages) Values(value PackageName) map[ModuleName]Package {
 
 	return result
 }
+
+// FilterPackages returns a copy of the versions only including the packages
+// matching fn, sameness is determined again using the remaining packages.
+func (v Versions) FilterPackages(fn func(ModuleName, Package) bool) Versions {
+	res := Versions{
+		Modules:    make(map[ModuleName]Module, len(v.Modules)),
+		GoVersions: v.GoVersions,
+		Workspaces: v.Workspaces,
+		Graphs:     v.Graphs,
+	}
+
+	for name, mod := range v.Modules {
+		res.Modules[name] = Module{
+			ModuleGoVersion:        mod.ModuleGoVersion,
+			File:                   mod.File,
+			DependencyRequirements: make(map[PackageName]Package),
+		}
+	}
+
+	for _, pkgName := range v.Packages.Names() {
+		for _, mod := range v.GoVersions.Values() {
+			pkg, ok := v.Modules[mod.Name].DependencyRequirements[pkgName]
+			if !ok || !fn(mod.Name, pkg) {
+				continue
+			}
+
+			res.Modules[mod.Name].DependencyRequirements[pkgName] = pkg
+			res.Packages.Set(mod.Name, pkg)
+		}
+	}
+
+	return res
+}
+
+// ModuleCoupling returns the coupling metrics of the Module, the afferent
+// coupling only counts the parsed modules requiring it.
+func (v Versions) ModuleCoupling(name ModuleName) Coupling {
+	return Coupling{
+		Afferent: len(v.Packages.Values(PackageName(name))),
+		Efferent: len(v.Modules[name].DependencyRequirements),
+	}
+}
+
+// PackageCoupling returns the coupling metrics of the Package, the efferent
+// coupling is only known when the package is one of the parsed modules,
+// otherwise it is 0.
+func (v Versions) PackageCoupling(name PackageName) Coupling {
+	return v.ModuleCoupling(ModuleName(name))
+}
+
+// Why returns, for each Module depending directly or transitively on the
+// package, the shortest chain of requirements explaining why the package is
+// needed. It requires the module graphs to be built using WithModuleGraph.
+func (v Versions) Why(name PackageName) map[ModuleName][]module.Version {
+	res := make(map[ModuleName][]module.Version)
+
+	for mod, graph := range v.Graphs {
+		if path := graph.Why(name); path != nil {
+			res[mod] = path
+		}
+	}
+
+	return res
+}
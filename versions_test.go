@@ -1,7 +1,11 @@
 package versions_test
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"go/build"
+	"io"
 	"os"
 	"path/filepath"
 	"testing"
@@ -9,8 +13,148 @@ import (
 	"github.com/google/go-cmp/cmp"
 
 	"github.com/MarioCarrion/versions"
+	"github.com/MarioCarrion/versions/goproxy"
+	"github.com/MarioCarrion/versions/osv"
 )
 
+type (
+	testRenderer string
+)
+
+func Test_Coupling_Instability(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    versions.Coupling
+		expected float64
+	}{
+		{
+			"OK: no coupling",
+			versions.Coupling{},
+			0,
+		},
+		{
+			"OK: stable",
+			versions.Coupling{Afferent: 2},
+			0,
+		},
+		{
+			"OK: unstable",
+			versions.Coupling{Efferent: 3},
+			1,
+		},
+		{
+			"OK: balanced",
+			versions.Coupling{Afferent: 1, Efferent: 3},
+			0.75,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if actual := test.input.Instability(); actual != test.expected {
+				t.Fatalf("expected %f, got %f", test.expected, actual)
+			}
+		})
+	}
+}
+
+func Test_Discover(t *testing.T) {
+	t.Parallel()
+
+	type (
+		input struct {
+			roots    []string
+			excludes []string
+		}
+
+		expected struct {
+			files []string
+			err   bool
+		}
+	)
+
+	tests := []struct {
+		name     string
+		input    input
+		expected expected
+	}{
+		{
+			"OK",
+			input{
+				roots: []string{"fixtures/discover"},
+			},
+			expected{
+				files: []string{
+					"fixtures/discover/b/go.mod",
+					"fixtures/discover/c/d/go.mod",
+					"fixtures/discover/go.mod",
+					"fixtures/discover/keep/ignored/go.mod",
+				},
+			},
+		},
+		{
+			"OK: excludes",
+			input{
+				roots:    []string{"fixtures/discover"},
+				excludes: []string{"d/", "/go.mod", "keep/*", "!keep/ignored"},
+			},
+			expected{
+				files: []string{
+					"fixtures/discover/b/go.mod",
+					"fixtures/discover/keep/ignored/go.mod",
+				},
+			},
+		},
+		{
+			"OK: multiple roots",
+			input{
+				roots: []string{"fixtures/discover/c", "fixtures/discover/b"},
+			},
+			expected{
+				files: []string{
+					"fixtures/discover/c/d/go.mod",
+					"fixtures/discover/b/go.mod",
+				},
+			},
+		},
+		{
+			"Invalid root",
+			input{
+				roots: []string{"fixtures/does_not_exist"},
+			},
+			expected{
+				err: true,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := versions.Discover(test.input.roots, test.input.excludes)
+			if (err != nil) != test.expected.err {
+				t.Fatalf("expected error %t, got %t", test.expected.err, err != nil)
+			}
+
+			var expected []string
+			for _, file := range test.expected.files {
+				expected = append(expected, filepath.FromSlash(file))
+			}
+
+			if !cmp.Equal(got, expected) {
+				t.Fatalf("expected values do not match: %s", cmp.Diff(got, expected))
+			}
+		})
+	}
+}
+
 func Test_GoVersions(t *testing.T) {
 	t.Parallel()
 
@@ -115,6 +259,7 @@ func Test_New(t *testing.T) {
 			modules    map[versions.ModuleName]versions.Module
 			goVersions []versions.ModuleGoVersion
 			packages   map[versions.PackageName]map[versions.ModuleName]versions.Package
+			workspaces []versions.Workspace
 			err        bool
 		}
 	)
@@ -146,9 +291,11 @@ func Test_New(t *testing.T) {
 							Name:      "fixture.com/new_module_indirect",
 							GoVersion: "1.14",
 						},
+						File: "fixtures/new_module_indirect.mod",
 						DependencyRequirements: map[versions.PackageName]versions.Package{
 							"github.com/MarioCarrion/indirect": {
 								Name:       "github.com/MarioCarrion/indirect",
+								License:    versions.License{Status: versions.LicenseStatusNotDownloaded},
 								Version:    "v0.0.1",
 								IsIndirect: true,
 							},
@@ -159,9 +306,11 @@ func Test_New(t *testing.T) {
 							Name:      "fixture.com/new_module_replace",
 							GoVersion: "1.14",
 						},
+						File: "fixtures/new_module_replace.mod",
 						DependencyRequirements: map[versions.PackageName]versions.Package{
 							"github.com/MarioCarrion/nit": {
 								Name:            "github.com/MarioCarrion/nit",
+								License:         versions.License{Status: versions.LicenseStatusNotDownloaded},
 								Version:         "v1.23.3",
 								ReplacedPath:    "replaced/MarioCarrion/nit",
 								ReplacedVersion: "v9.0.0",
@@ -173,13 +322,16 @@ func Test_New(t *testing.T) {
 							Name:      "fixture.com/new_module_simple",
 							GoVersion: "1.13",
 						},
+						File: "fixtures/new_module_simple.mod",
 						DependencyRequirements: map[versions.PackageName]versions.Package{
 							"github.com/MarioCarrion/nit": {
 								Name:    "github.com/MarioCarrion/nit",
+								License: versions.License{Status: versions.LicenseStatusNotDownloaded},
 								Version: "v1.23.1",
 							},
 							"github.com/MarioCarrion/swagger-lint": {
 								Name:    "github.com/MarioCarrion/swagger-lint",
+								License: versions.License{Status: versions.LicenseStatusNotDownloaded},
 								Version: "v1.0.0",
 							},
 						},
@@ -203,6 +355,7 @@ func Test_New(t *testing.T) {
 					"github.com/MarioCarrion/indirect": {
 						"fixture.com/new_module_indirect": {
 							Name:       "github.com/MarioCarrion/indirect",
+							License:    versions.License{Status: versions.LicenseStatusNotDownloaded},
 							Version:    "v0.0.1",
 							IsIndirect: true,
 						},
@@ -210,24 +363,95 @@ func Test_New(t *testing.T) {
 					"github.com/MarioCarrion/nit": {
 						"fixture.com/new_module_replace": {
 							Name:            "github.com/MarioCarrion/nit",
+							License:         versions.License{Status: versions.LicenseStatusNotDownloaded},
 							Version:         "v1.23.3",
 							ReplacedPath:    "replaced/MarioCarrion/nit",
 							ReplacedVersion: "v9.0.0",
 						},
 						"fixture.com/new_module_simple": {
 							Name:    "github.com/MarioCarrion/nit",
+							License: versions.License{Status: versions.LicenseStatusNotDownloaded},
 							Version: "v1.23.1",
 						},
 					},
 					"github.com/MarioCarrion/swagger-lint": {
 						"fixture.com/new_module_simple": {
 							Name:    "github.com/MarioCarrion/swagger-lint",
+							License: versions.License{Status: versions.LicenseStatusNotDownloaded},
 							Version: "v1.0.0",
 						},
 					},
 				},
 			},
 		},
+		{
+			"OK: Workspace",
+			[]string{
+				"fixtures/workspace/go.work",
+			},
+			expected{
+				modules: map[versions.ModuleName]versions.Module{
+					"fixture.com/workspace/one": {
+						ModuleGoVersion: versions.ModuleGoVersion{
+							Name:      "fixture.com/workspace/one",
+							GoVersion: "1.21",
+						},
+						File: "fixtures/workspace/one/go.mod",
+						DependencyRequirements: map[versions.PackageName]versions.Package{
+							"github.com/MarioCarrion/nit": {
+								Name:            "github.com/MarioCarrion/nit",
+								License:         versions.License{Status: versions.LicenseStatusNotDownloaded},
+								Version:         "v1.23.1",
+								ReplacedPath:    "github.com/MarioCarrion/nit",
+								ReplacedVersion: "v1.24.0",
+							},
+							"github.com/MarioCarrion/swagger-lint": {
+								Name:         "github.com/MarioCarrion/swagger-lint",
+								License:      versions.License{Status: versions.LicenseStatusNotDownloaded},
+								Version:      "v1.0.0",
+								IsIndirect:   true,
+								ReplacedPath: "../swagger-lint",
+							},
+						},
+					},
+					"fixture.com/workspace/two": {
+						ModuleGoVersion: versions.ModuleGoVersion{
+							Name:      "fixture.com/workspace/two",
+							GoVersion: "1.20",
+						},
+						File: "fixtures/workspace/two/go.mod",
+						DependencyRequirements: map[versions.PackageName]versions.Package{
+							"github.com/MarioCarrion/nit": {
+								Name:    "github.com/MarioCarrion/nit",
+								License: versions.License{Status: versions.LicenseStatusNotDownloaded},
+								Version: "v1.23.3",
+							},
+						},
+					},
+				},
+				goVersions: []versions.ModuleGoVersion{
+					{
+						Name:      "fixture.com/workspace/one",
+						GoVersion: "1.21",
+					},
+					{
+						Name:      "fixture.com/workspace/two",
+						GoVersion: "1.20",
+					},
+				},
+				workspaces: []versions.Workspace{
+					{
+						Path:      "fixtures/workspace/go.work",
+						GoVersion: "1.21.3",
+						Toolchain: "go1.21.5",
+						Modules: []versions.ModuleName{
+							"fixture.com/workspace/one",
+							"fixture.com/workspace/two",
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -248,6 +472,10 @@ func Test_New(t *testing.T) {
 				t.Fatalf("expected goversions do not match: %s", cmp.Diff(goversions, test.expected.goVersions))
 			}
 
+			if !cmp.Equal(got.Workspaces, test.expected.workspaces) {
+				t.Fatalf("expected workspaces do not match: %s", cmp.Diff(got.Workspaces, test.expected.workspaces))
+			}
+
 			for pkg, expectedPkg := range test.expected.packages {
 				if packages := got.Packages.Values(pkg); !cmp.Equal(packages, expectedPkg) {
 					t.Fatalf("expected goversions do not match: %s", cmp.Diff(packages, expectedPkg))
@@ -257,6 +485,91 @@ func Test_New(t *testing.T) {
 	}
 }
 
+func Test_NewContext(t *testing.T) {
+	t.Parallel()
+
+	files := []string{
+		"fixtures/new_module_indirect.mod",
+		"fixtures/new_module_replace.mod",
+		"fixtures/new_module_simple.mod",
+	}
+
+	t.Run("OK: deterministic", func(t *testing.T) {
+		t.Parallel()
+
+		serial, err := versions.NewContext(context.Background(), files, versions.WithConcurrency(1))
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		concurrent, err := versions.NewContext(context.Background(), files, versions.WithConcurrency(8))
+		if err != nil {
+			t.Fatalf("expected no error, got %s", err)
+		}
+
+		if !cmp.Equal(serial.Modules, concurrent.Modules) {
+			t.Fatalf("expected modules do not match: %s", cmp.Diff(serial.Modules, concurrent.Modules))
+		}
+	})
+
+	t.Run("ERR: canceled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		if _, err := versions.NewContext(ctx, files); !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+	})
+}
+
+func Test_PURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    versions.Package
+		expected string
+	}{
+		{
+			"OK",
+			versions.Package{Name: "github.com/MarioCarrion/nit", Version: "v1.0.0", SelectedVersion: "v1.1.0"},
+			"pkg:golang/github.com/MarioCarrion/nit@v1.1.0",
+		},
+		{
+			"OK: escaped",
+			versions.Package{Name: "github.com/example/pkg", Version: "v2.0.0+incompatible"},
+			"pkg:golang/github.com/example/pkg@v2.0.0%2Bincompatible",
+		},
+		{
+			"OK: replaced",
+			versions.Package{Name: "github.com/example/pkg", Version: "v1.0.0", ReplacedPath: "github.com/fork/pkg", ReplacedVersion: "v1.2.0"},
+			"pkg:golang/github.com/fork/pkg@v1.2.0",
+		},
+		{
+			"OK: local replacement",
+			versions.Package{Name: "github.com/example/pkg", Version: "v1.0.0", ReplacedPath: "../pkg"},
+			"",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := test.input.PURL(); got != test.expected {
+				t.Fatalf("expected %s, got %s", test.expected, got)
+			}
+		})
+	}
+
+	if got := versions.PURL("fixture.com/module", ""); got != "pkg:golang/fixture.com/module" {
+		t.Fatalf("expected no version, got %s", got)
+	}
+}
+
 func Test_Package(t *testing.T) {
 	t.Parallel()
 
@@ -372,6 +685,43 @@ func Test_Package(t *testing.T) {
 	}
 }
 
+func Test_Package_BuiltVersion(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    versions.Package
+		expected string
+	}{
+		{
+			"OK",
+			versions.Package{Version: "v1.0.0", SelectedVersion: "v1.1.0"},
+			"v1.1.0",
+		},
+		{
+			"OK: replaced",
+			versions.Package{Version: "v1.0.0", SelectedVersion: "v1.1.0", ReplacedPath: "github.com/fork/pkg", ReplacedVersion: "v1.2.0"},
+			"v1.2.0",
+		},
+		{
+			"OK: local replacement",
+			versions.Package{Version: "v1.0.0", ReplacedPath: "../pkg"},
+			"",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := test.input.BuiltVersion(); got != test.expected {
+				t.Fatalf("expected %s, got %s", test.expected, got)
+			}
+		})
+	}
+}
+
 func Test_Packages(t *testing.T) {
 	t.Parallel()
 
@@ -483,6 +833,133 @@ func Test_Packages(t *testing.T) {
 				},
 			},
 		},
+		{
+			"Different license and indirect, same version",
+			[]input{
+				{
+					"Module1",
+					versions.Package{
+						Name:       "pkg1",
+						Version:    "v1.0.0",
+						IsIndirect: true,
+					},
+				},
+				{
+					"Module2",
+					versions.Package{
+						Name:    "pkg1",
+						Version: "v1.0.0",
+						License: versions.License{Identifier: "MIT"},
+					},
+				},
+			},
+			expected{
+				map[versions.PackageName]bool{
+					"pkg1": true,
+				},
+				map[versions.PackageName]map[versions.ModuleName]versions.Package{
+					"pkg1": {
+						"Module1": versions.Package{
+							Name:       "pkg1",
+							Version:    "v1.0.0",
+							IsIndirect: true,
+						},
+						"Module2": versions.Package{
+							Name:    "pkg1",
+							Version: "v1.0.0",
+							License: versions.License{Identifier: "MIT"},
+						},
+					},
+				},
+				[]versions.PackageName{
+					"pkg1",
+				},
+			},
+		},
+		{
+			"Same version, different replacement",
+			[]input{
+				{
+					"Module1",
+					versions.Package{
+						Name:         "pkg1",
+						Version:      "v1.0.0",
+						ReplacedPath: "../pkg1",
+					},
+				},
+				{
+					"Module2",
+					versions.Package{
+						Name:    "pkg1",
+						Version: "v1.0.0",
+					},
+				},
+			},
+			expected{
+				map[versions.PackageName]bool{
+					"pkg1": false,
+				},
+				map[versions.PackageName]map[versions.ModuleName]versions.Package{
+					"pkg1": {
+						"Module1": versions.Package{
+							Name:         "pkg1",
+							Version:      "v1.0.0",
+							ReplacedPath: "../pkg1",
+						},
+						"Module2": versions.Package{
+							Name:    "pkg1",
+							Version: "v1.0.0",
+						},
+					},
+				},
+				[]versions.PackageName{
+					"pkg1",
+				},
+			},
+		},
+		{
+			"Different declared versions, same selected version",
+			[]input{
+				{
+					"Module1",
+					versions.Package{
+						Name:            "pkg1",
+						Version:         "v1",
+						SelectedVersion: "v2",
+					},
+				},
+				{
+					"Module2",
+					versions.Package{
+						Name:            "pkg1",
+						Version:         "v2",
+						SelectedVersion: "v2",
+					},
+				},
+			},
+			expected{
+				map[versions.PackageName]bool{
+					"pkg1": true,
+				},
+				map[versions.PackageName]map[versions.ModuleName]versions.Package{
+					"pkg1": {
+						"Module1": versions.Package{
+							Name:            "pkg1",
+							Version:         "v1",
+							SelectedVersion: "v2",
+						},
+						"Module2": versions.Package{
+							Name:            "pkg1",
+							Version:         "v2",
+							SelectedVersion: "v2",
+						},
+					},
+				},
+				[]versions.PackageName{
+					"pkg1",
+				},
+			},
+		},
 		{
 			"Different modules",
 			[]input{
@@ -585,3 +1062,449 @@ func Test_Packages(t *testing.T) {
 		})
 	}
 }
+
+func Test_Packages_Drift(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		input           []string
+		expectedHighest string
+		expectedDrift   versions.Drift
+		replacedPath    string
+	}{
+		{
+			"OK: same",
+			[]string{"v1.2.3", "v1.2.3"},
+			"v1.2.3",
+			versions.DriftNone,
+			"",
+		},
+		{
+			"OK: patch",
+			[]string{"v1.2.3", "v1.2.4", "v1.2.0"},
+			"v1.2.4",
+			versions.DriftPatch,
+			"",
+		},
+		{
+			"OK: minor",
+			[]string{"v1.2.3", "v1.3.0", "v1.3.1"},
+			"v1.3.1",
+			versions.DriftMinor,
+			"",
+		},
+		{
+			"OK: pseudo",
+			[]string{"v1.2.3", "v1.2.4-0.20200617184744-03fbc970a7f7"},
+			"v1.2.4-0.20200617184744-03fbc970a7f7",
+			versions.DriftPseudo,
+			"",
+		},
+		{
+			"OK: major",
+			[]string{"v1.2.3", "v2.0.0+incompatible", "v1.2.4"},
+			"v2.0.0+incompatible",
+			versions.DriftMajor,
+			"",
+		},
+		{
+			"OK: replaced",
+			[]string{"v1.2.3", "v1.2.3"},
+			"v1.2.3",
+			versions.DriftReplaced,
+			"../local",
+		},
+		{
+			"OK: no valid versions",
+			[]string{"", ""},
+			"",
+			versions.DriftNone,
+			"",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			var packages versions.Packages
+
+			for i, version := range test.input {
+				pkg := versions.Package{Name: "pkg", Version: version}
+				if i == 0 {
+					pkg.ReplacedPath = test.replacedPath
+				}
+
+				packages.Set(versions.ModuleName(fmt.Sprintf("Module%d", i)), pkg)
+			}
+
+			if actual := packages.Highest("pkg"); actual != test.expectedHighest {
+				t.Fatalf("expected %s, got %s", test.expectedHighest, actual)
+			}
+
+			if actual := packages.Drift("pkg"); actual != test.expectedDrift {
+				t.Fatalf("expected %s, got %s", test.expectedDrift, actual)
+			}
+		})
+	}
+}
+
+func Test_Renderers(t *testing.T) {
+	t.Parallel()
+
+	versions.RegisterRenderer("test_renderer", func(v versions.Versions, o versions.RendererOptions) versions.Renderer {
+		return testRenderer(fmt.Sprintf("%d modules, coupling %t", len(v.Modules), o.Coupling))
+	})
+	defer versions.UnregisterRenderer("test_renderer")
+
+	renderer, err := versions.NewRenderer("test_renderer", versions.Versions{})
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if got := renderer.String(); got != "0 modules, coupling false" {
+		t.Fatalf("expected \"0 modules, coupling false\", got %s", got)
+	}
+
+	renderer, err = versions.NewRenderer("test_renderer", versions.Versions{}, versions.WithRendererCoupling(true))
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	if got := renderer.String(); got != "0 modules, coupling true" {
+		t.Fatalf("expected \"0 modules, coupling true\", got %s", got)
+	}
+
+	if _, err := versions.NewRenderer("test_unknown", versions.Versions{}); err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+
+	found := false
+
+	for _, name := range versions.Renderers() {
+		if name == "test_renderer" {
+			found = true
+		}
+	}
+
+	if !found {
+		t.Fatalf("expected renderer to be registered")
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatalf("expected panic")
+			}
+		}()
+
+		versions.RegisterRenderer("test_renderer", func(v versions.Versions, _ versions.RendererOptions) versions.Renderer {
+			return testRenderer("")
+		})
+	}()
+}
+
+func Test_VersionDrift(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		a, b     string
+		expected versions.Drift
+		severity int
+	}{
+		{"OK: none", "v1.2.3", "v1.2.3", versions.DriftNone, 0},
+		{"OK: none, shorthand", "v1", "v1.0.0", versions.DriftNone, 0},
+		{"OK: patch", "v1.2.3", "v1.2.4", versions.DriftPatch, 1},
+		{"OK: patch, pre-release", "v1.2.3-rc.1", "v1.2.3", versions.DriftPatch, 1},
+		{"OK: minor", "v1.3.0", "v1.2.3", versions.DriftMinor, 2},
+		{"OK: pseudo", "v0.0.0-20191014201558-431d9a760f2d", "v0.1.0", versions.DriftPseudo, 3},
+		{"OK: major", "v1.2.3", "v2.0.0", versions.DriftMajor, 4},
+		{"OK: major, pseudo", "v0.0.0-20191014201558-431d9a760f2d", "v1.0.0", versions.DriftMajor, 4},
+		{"OK: major, invalid", "v1.2.3", "latest", versions.DriftMajor, 4},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if actual := versions.VersionDrift(test.a, test.b); actual != test.expected {
+				t.Fatalf("expected %q, got %q", test.expected, actual)
+			}
+
+			if actual := versions.VersionDrift(test.b, test.a); actual != test.expected {
+				t.Fatalf("expected %q reversed, got %q", test.expected, actual)
+			}
+
+			if actual := test.expected.Severity(); actual != test.severity {
+				t.Fatalf("expected severity %d, got %d", test.severity, actual)
+			}
+		})
+	}
+}
+
+func Test_Versions_Coupling(t *testing.T) {
+	t.Parallel()
+
+	var v versions.Versions
+
+	v.Modules = map[versions.ModuleName]versions.Module{
+		"Module1": {
+			ModuleGoVersion: versions.ModuleGoVersion{Name: "Module1", GoVersion: "1.15"},
+			DependencyRequirements: map[versions.PackageName]versions.Package{
+				"Module2": {Name: "Module2", Version: "v1"},
+				"pkg1":    {Name: "pkg1", Version: "v1"},
+				"pkg2":    {Name: "pkg2", Version: "v1"},
+			},
+		},
+		"Module2": {
+			ModuleGoVersion: versions.ModuleGoVersion{Name: "Module2", GoVersion: "1.15"},
+			DependencyRequirements: map[versions.PackageName]versions.Package{
+				"pkg1": {Name: "pkg1", Version: "v2"},
+			},
+		},
+	}
+
+	for _, mod := range []versions.ModuleName{"Module1", "Module2"} {
+		v.GoVersions.Set(mod, "1.15")
+
+		for _, pkg := range v.Modules[mod].DependencyRequirements {
+			v.Packages.Set(mod, pkg)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		actual   versions.Coupling
+		expected versions.Coupling
+	}{
+		{
+			"OK: Module1",
+			v.ModuleCoupling("Module1"),
+			versions.Coupling{Efferent: 3},
+		},
+		{
+			"OK: Module2",
+			v.ModuleCoupling("Module2"),
+			versions.Coupling{Afferent: 1, Efferent: 1},
+		},
+		{
+			"OK: package is module",
+			v.PackageCoupling("Module2"),
+			versions.Coupling{Afferent: 1, Efferent: 1},
+		},
+		{
+			"OK: package",
+			v.PackageCoupling("pkg1"),
+			versions.Coupling{Afferent: 2},
+		},
+		{
+			"OK: unknown",
+			v.PackageCoupling("unknown"),
+			versions.Coupling{},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if !cmp.Equal(test.actual, test.expected) {
+				t.Fatalf("expected values do not match: %s", cmp.Diff(test.expected, test.actual))
+			}
+		})
+	}
+}
+
+func Test_Versions_FilterPackages(t *testing.T) {
+	t.Parallel()
+
+	var (
+		detected = versions.License{Identifier: "MIT", Status: versions.LicenseStatusDetected}
+		notFound = versions.License{Status: versions.LicenseStatusNotFound}
+	)
+
+	var v versions.Versions
+
+	v.Modules = map[versions.ModuleName]versions.Module{
+		"Module1": {
+			ModuleGoVersion: versions.ModuleGoVersion{Name: "Module1", GoVersion: "1.15"},
+			DependencyRequirements: map[versions.PackageName]versions.Package{
+				"pkg1": {Name: "pkg1", Version: "v1", License: detected},
+				"pkg2": {Name: "pkg2", Version: "v1", License: notFound},
+			},
+		},
+		"Module2": {
+			ModuleGoVersion: versions.ModuleGoVersion{Name: "Module2", GoVersion: "1.15"},
+			DependencyRequirements: map[versions.PackageName]versions.Package{
+				"pkg2": {Name: "pkg2", Version: "v2", License: detected},
+			},
+		},
+	}
+
+	for _, mod := range []versions.ModuleName{"Module1", "Module2"} {
+		v.GoVersions.Set(mod, "1.15")
+
+		for _, name := range []versions.PackageName{"pkg1", "pkg2"} {
+			if pkg, ok := v.Modules[mod].DependencyRequirements[name]; ok {
+				v.Packages.Set(mod, pkg)
+			}
+		}
+	}
+
+	actual := v.FilterPackages(func(_ versions.ModuleName, pkg versions.Package) bool {
+		return pkg.License.Status == versions.LicenseStatusDetected
+	})
+
+	expected := map[versions.ModuleName]versions.Module{
+		"Module1": {
+			ModuleGoVersion: versions.ModuleGoVersion{Name: "Module1", GoVersion: "1.15"},
+			DependencyRequirements: map[versions.PackageName]versions.Package{
+				"pkg1": {Name: "pkg1", Version: "v1", License: detected},
+			},
+		},
+		"Module2": {
+			ModuleGoVersion: versions.ModuleGoVersion{Name: "Module2", GoVersion: "1.15"},
+			DependencyRequirements: map[versions.PackageName]versions.Package{
+				"pkg2": {Name: "pkg2", Version: "v2", License: detected},
+			},
+		},
+	}
+
+	if !cmp.Equal(actual.Modules, expected) {
+		t.Fatalf("expected values do not match: %s", cmp.Diff(actual.Modules, expected))
+	}
+
+	if names := actual.Packages.Names(); !cmp.Equal(names, []versions.PackageName{"pkg1", "pkg2"}) {
+		t.Fatalf("expected names do not match: %s", names)
+	}
+
+	if !actual.Packages.IsSame("pkg2") {
+		t.Fatalf("expected pkg2 to be the same after filtering")
+	}
+
+	if v.Packages.IsSame("pkg2") {
+		t.Fatalf("expected original pkg2 to be different")
+	}
+}
+
+func Test_WithUpdates(t *testing.T) {
+	t.Parallel()
+
+	dir, err := filepath.Abs(filepath.Join("fixtures", "goproxy"))
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected map[versions.PackageName]versions.Updates
+	}{
+		{
+			"OK",
+			"file://" + filepath.ToSlash(dir),
+			map[versions.PackageName]versions.Updates{
+				"github.com/MarioCarrion/nit": {
+					Patch: "v1.23.3",
+					Minor: "v1.24.0",
+				},
+				"github.com/MarioCarrion/swagger-lint": {},
+			},
+		},
+		{
+			"OK: off",
+			"off",
+			map[versions.PackageName]versions.Updates{
+				"github.com/MarioCarrion/nit":          {},
+				"github.com/MarioCarrion/swagger-lint": {},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, err := goproxy.NewClient(test.input)
+			if err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+
+			got, err := versions.New([]string{"fixtures/new_module_simple.mod"}, versions.WithUpdates(client))
+			if err != nil {
+				t.Fatalf("expected no error, got %s", err)
+			}
+
+			for name, expected := range test.expected {
+				pkg := got.Modules["fixture.com/new_module_simple"].DependencyRequirements[name]
+				if !cmp.Equal(pkg.Updates, expected) {
+					t.Fatalf("expected values do not match: %s", cmp.Diff(pkg.Updates, expected))
+				}
+			}
+		})
+	}
+}
+
+func Test_WithVulnerabilities(t *testing.T) {
+	t.Parallel()
+
+	client, err := osv.NewClient(filepath.Join("fixtures", "osv"))
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	got, err := versions.New([]string{"fixtures/new_module_vulnerable.mod"},
+		versions.WithoutLicenses(),
+		versions.WithVulnerabilities(client))
+	if err != nil {
+		t.Fatalf("expected no error, got %s", err)
+	}
+
+	expected := map[versions.PackageName][]versions.Vulnerability{
+		"github.com/example/local": nil,
+		"github.com/example/patched": {
+			{
+				ID:      "GO-2023-0003",
+				Summary: "Path traversal in github.com/example/replacement",
+				Fixed:   "v2.0.1",
+			},
+		},
+		"github.com/example/vulnerable": {
+			{
+				ID:       "GO-2021-0001",
+				Aliases:  []string{"CVE-2021-0001", "GHSA-xxxx-yyyy-zzzz"},
+				Summary:  "Denial of service in github.com/example/vulnerable",
+				Severity: "HIGH",
+				Fixed:    "v1.2.0",
+			},
+			{
+				ID:       "GO-2022-0002",
+				Aliases:  []string{"CVE-2022-0002"},
+				Summary:  "Unfixed information disclosure in github.com/example/vulnerable",
+				Severity: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:N/A:N",
+			},
+		},
+	}
+
+	for name, expected := range expected {
+		pkg := got.Modules["fixture.com/new_module_vulnerable"].DependencyRequirements[name]
+		if !cmp.Equal(pkg.Vulnerabilities, expected) {
+			t.Fatalf("%s: expected values do not match: %s", name, cmp.Diff(pkg.Vulnerabilities, expected))
+		}
+	}
+}
+
+func (r testRenderer) Render(w io.Writer) error {
+	_, err := io.WriteString(w, string(r))
+	return err
+}
+
+func (r testRenderer) String() string {
+	return string(r)
+}
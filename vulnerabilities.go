@@ -0,0 +1,30 @@
+package versions
+
+import (
+	"context"
+
+	"golang.org/x/mod/module"
+
+	"github.com/MarioCarrion/versions/osv"
+)
+
+func newVulnerabilities(ctx context.Context, client *osv.Client, mod module.Version) ([]Vulnerability, error) {
+	matches, err := client.Affecting(ctx, mod.Path, mod.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []Vulnerability
+
+	for _, match := range matches {
+		res = append(res, Vulnerability{
+			ID:       match.Entry.ID,
+			Aliases:  match.Entry.Aliases,
+			Summary:  match.Entry.Summary,
+			Severity: match.Entry.SeverityLevel(),
+			Fixed:    match.Fixed,
+		})
+	}
+
+	return res, nil
+}